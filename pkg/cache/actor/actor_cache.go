@@ -0,0 +1,104 @@
+package actor
+
+import (
+	"LRU_cache/pkg/cache"
+	"sync"
+)
+
+// command - операция над внутренним кэшем, выполняемая горутиной-актором
+type command struct {
+	op    func(inner cache.Cache)
+	reply chan struct{}
+}
+
+// ActorCache - обёртка, выполняющая все операции над внутренним кэшем в одной горутине.
+// Внутренний кэш остаётся однопоточным, конкурентный доступ сериализуется через канал команд
+type ActorCache struct {
+	inner    cache.Cache
+	commands chan command
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewActorCache создает актор над inner и запускает обрабатывающую горутину.
+// После использования кэш нужно остановить вызовом Close
+func NewActorCache(inner cache.Cache) cache.Cache {
+	a := &ActorCache{
+		inner:    inner,
+		commands: make(chan command),
+		done:     make(chan struct{}),
+	}
+	go a.loop()
+	return a
+}
+
+// loop последовательно выполняет команды до остановки актора
+func (a *ActorCache) loop() {
+	for {
+		select {
+		case cmd := <-a.commands:
+			cmd.op(a.inner)
+			close(cmd.reply)
+		case <-a.done:
+			return
+		}
+	}
+}
+
+// do отправляет операцию актору и ждёт её завершения, возвращает false, если актор остановлен
+func (a *ActorCache) do(op func(inner cache.Cache)) bool {
+	cmd := command{op: op, reply: make(chan struct{})}
+	select {
+	case a.commands <- cmd:
+	case <-a.done:
+		return false
+	}
+	<-cmd.reply
+	return true
+}
+
+// Add добавляет значение через актор. После Close возвращает false
+func (a *ActorCache) Add(key, value interface{}) (ok bool) {
+	a.do(func(inner cache.Cache) {
+		ok = inner.Add(key, value)
+	})
+	return ok
+}
+
+// Get читает значение через актор. После Close всегда промах
+func (a *ActorCache) Get(key interface{}) (value interface{}, ok bool) {
+	a.do(func(inner cache.Cache) {
+		value, ok = inner.Get(key)
+	})
+	return value, ok
+}
+
+// Remove удаляет значение через актор. После Close возвращает false
+func (a *ActorCache) Remove(key interface{}) (ok bool) {
+	a.do(func(inner cache.Cache) {
+		ok = inner.Remove(key)
+	})
+	return ok
+}
+
+// Len возвращает размер внутреннего кэша через актор. После Close возвращает 0
+func (a *ActorCache) Len() (n int) {
+	a.do(func(inner cache.Cache) {
+		n = inner.Len()
+	})
+	return n
+}
+
+// Clear очищает внутренний кэш через актор. После Close ничего не делает
+func (a *ActorCache) Clear() {
+	a.do(func(inner cache.Cache) {
+		inner.Clear()
+	})
+}
+
+// Close останавливает горутину-актор. Повторный вызов безопасен
+func (a *ActorCache) Close() {
+	a.once.Do(func() {
+		close(a.done)
+	})
+}
@@ -0,0 +1,131 @@
+package actor
+
+import (
+	"LRU_cache/pkg/cache"
+	"LRU_cache/pkg/cache/lru"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestActorCache_Basic проверяет, что операции прозрачно доходят до внутреннего кэша
+func TestActorCache_Basic(t *testing.T) {
+	c := NewActorCache(lru.NewLRUCache(2))
+	defer c.(*ActorCache).Close()
+
+	assert.True(t, c.Add("key1", "value1"))
+	assert.False(t, c.Add("key1", "value2"), "Duplicate key should return false")
+
+	val, ok := c.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value2", val)
+
+	assert.True(t, c.Remove("key1"))
+	assert.False(t, c.Remove("key1"))
+}
+
+// TestActorCache_Concurrent нагружает актор из множества горутин (запускать с -race)
+func TestActorCache_Concurrent(t *testing.T) {
+	c := NewActorCache(lru.NewLRUCache(64))
+	defer c.(*ActorCache).Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("key%d", (g*31+i)%128)
+				c.Add(key, i)
+				c.Get(key)
+				if i%7 == 0 {
+					c.Remove(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	inner := c.(*ActorCache).inner.(*lru.LRU)
+	assert.LessOrEqual(t, inner.Len(), 64, "Inner cache should respect its capacity")
+}
+
+// TestActorCache_Close проверяет поведение после остановки и повторный Close
+func TestActorCache_Close(t *testing.T) {
+	c := NewActorCache(lru.NewLRUCache(2))
+	c.Add("key1", "value1")
+
+	c.(*ActorCache).Close()
+	c.(*ActorCache).Close()
+
+	assert.False(t, c.Add("key2", "value2"), "Add after Close should fail")
+	_, ok := c.Get("key1")
+	assert.False(t, ok, "Get after Close should miss")
+	assert.False(t, c.Remove("key1"), "Remove after Close should fail")
+}
+
+// mutexCache - эталонная обёртка с мьютексом для сравнения в бенчмарках
+type mutexCache struct {
+	mu    sync.Mutex
+	inner cache.Cache
+}
+
+func (m *mutexCache) Add(key, value interface{}) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inner.Add(key, value)
+}
+
+func (m *mutexCache) Get(key interface{}) (interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inner.Get(key)
+}
+
+func (m *mutexCache) Remove(key interface{}) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inner.Remove(key)
+}
+
+func (m *mutexCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inner.Len()
+}
+
+func (m *mutexCache) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inner.Clear()
+}
+
+func benchmarkParallel(b *testing.B, c cache.Cache) {
+	for i := 0; i < 1024; i++ {
+		c.Add(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%4 == 0 {
+				c.Add(i%2048, i)
+			} else {
+				c.Get(i % 2048)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkActorCache(b *testing.B) {
+	c := NewActorCache(lru.NewLRUCache(1024))
+	defer c.(*ActorCache).Close()
+	benchmarkParallel(b, c)
+}
+
+func BenchmarkMutexCache(b *testing.B) {
+	benchmarkParallel(b, &mutexCache{inner: lru.NewLRUCache(1024)})
+}
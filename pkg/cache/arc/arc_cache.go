@@ -0,0 +1,177 @@
+package arc
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/list"
+)
+
+// entry - элемент одного из четырёх списков ARC. У призрачных записей (B1, B2) значение не хранится
+type entry struct {
+	key   interface{}
+	value interface{}
+	list  *list.List // список, в котором сейчас находится элемент
+}
+
+// ARC - Adaptive Replacement Cache. Резидентные элементы хранятся в T1 (встречались один раз)
+// и T2 (встречались повторно), а B1 и B2 - призрачные списки недавно вытесненных из них ключей.
+// Целевой размер T1 (p) подстраивается при попаданиях в призрачные списки
+type ARC struct {
+	capacity int
+	p        int // целевой размер T1
+
+	t1, t2 *list.List // резидентные элементы, в начале самые недавние
+	b1, b2 *list.List // призрачные ключи, вытесненные из T1 и T2
+
+	items map[interface{}]*list.Element // ключ -> элемент в одном из четырёх списков
+}
+
+// NewARCCache создает ARC кэш на n резидентных элементов; призрачные списки хранят ещё не более n ключей
+func NewARCCache(n int) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	return &ARC{
+		capacity: n,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[interface{}]*list.Element),
+	}
+}
+
+// Add добавляет значение. Существующий резидентный ключ обновляется, переходит в T2 и возвращается false.
+// Ключ из призрачного списка сдвигает целевой размер p и сразу попадает в T2
+func (a *ARC) Add(key, value interface{}) bool {
+	if element, ok := a.items[key]; ok {
+		e := element.Value.(*entry)
+		switch e.list {
+		case a.t1, a.t2:
+			e.value = value
+			a.moveTo(element, a.t2)
+			return false
+		case a.b1:
+			a.p = minInt(a.capacity, a.p+maxInt(a.b2.Len()/a.b1.Len(), 1))
+			a.replace(false)
+			e.value = value
+			a.moveTo(element, a.t2)
+			return true
+		case a.b2:
+			a.p = maxInt(0, a.p-maxInt(a.b1.Len()/a.b2.Len(), 1))
+			a.replace(true)
+			e.value = value
+			a.moveTo(element, a.t2)
+			return true
+		}
+	}
+
+	// Новый ключ
+	if a.t1.Len()+a.b1.Len() == a.capacity {
+		if a.t1.Len() < a.capacity {
+			a.removeBack(a.b1)
+			a.replace(false)
+		} else {
+			a.removeBack(a.t1)
+		}
+	} else if total := a.t1.Len() + a.t2.Len() + a.b1.Len() + a.b2.Len(); total >= a.capacity {
+		if total == 2*a.capacity {
+			a.removeBack(a.b2)
+		}
+		a.replace(false)
+	}
+
+	a.items[key] = a.t1.PushFront(&entry{key: key, value: value, list: a.t1})
+	return true
+}
+
+// Get возвращает резидентное значение; попадание переносит элемент в начало T2
+func (a *ARC) Get(key interface{}) (value interface{}, ok bool) {
+	element, exists := a.items[key]
+	if !exists {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if e.list != a.t1 && e.list != a.t2 {
+		return nil, false
+	}
+	a.moveTo(element, a.t2)
+	return e.value, true
+}
+
+// Remove удаляет резидентный элемент. Призрачная запись для ключа тоже забывается, но не считается удалением
+func (a *ARC) Remove(key interface{}) (ok bool) {
+	element, exists := a.items[key]
+	if !exists {
+		return false
+	}
+	e := element.Value.(*entry)
+	e.list.Remove(element)
+	delete(a.items, key)
+	return e.list == a.t1 || e.list == a.t2
+}
+
+// Len возвращает количество резидентных элементов
+func (a *ARC) Len() int {
+	return a.t1.Len() + a.t2.Len()
+}
+
+// Clear удаляет резидентные элементы и призрачные ключи и сбрасывает адаптацию p
+func (a *ARC) Clear() {
+	a.p = 0
+	a.t1.Init()
+	a.t2.Init()
+	a.b1.Init()
+	a.b2.Init()
+	a.items = make(map[interface{}]*list.Element)
+}
+
+// replace вытесняет резидентный элемент из T1 или T2 в соответствующий призрачный список
+func (a *ARC) replace(inB2 bool) {
+	t1Len := a.t1.Len()
+	if t1Len > 0 && (t1Len > a.p || (inB2 && t1Len == a.p) || a.t2.Len() == 0) {
+		a.demote(a.t1.Back(), a.b1)
+	} else if a.t2.Len() > 0 {
+		a.demote(a.t2.Back(), a.b2)
+	}
+}
+
+// demote переносит элемент в начало призрачного списка, освобождая значение
+func (a *ARC) demote(element *list.Element, ghost *list.List) {
+	e := element.Value.(*entry)
+	e.value = nil
+	a.moveTo(element, ghost)
+}
+
+// moveTo переносит элемент в начало списка target
+func (a *ARC) moveTo(element *list.Element, target *list.List) {
+	e := element.Value.(*entry)
+	if e.list == target {
+		target.MoveToFront(element)
+		return
+	}
+	e.list.Remove(element)
+	e.list = target
+	a.items[e.key] = target.PushFront(e)
+}
+
+// removeBack полностью удаляет самый старый элемент списка
+func (a *ARC) removeBack(l *list.List) {
+	if element := l.Back(); element != nil {
+		e := l.Remove(element).(*entry)
+		delete(a.items, e.key)
+	}
+}
+
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
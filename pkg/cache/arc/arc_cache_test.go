@@ -0,0 +1,87 @@
+package arc
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestARC_Basic проверяет базовые операции интерфейса
+func TestARC_Basic(t *testing.T) {
+	arc := NewARCCache(2).(*ARC)
+
+	assert.True(t, arc.Add("a", 1))
+	assert.False(t, arc.Add("a", 10), "Existing key should return false")
+
+	val, ok := arc.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = arc.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, arc.Remove("a"))
+	assert.False(t, arc.Remove("a"))
+	assert.Equal(t, 0, arc.Len())
+}
+
+// TestARC_PromotionToT2 проверяет, что повторное обращение переносит ключ в T2
+func TestARC_PromotionToT2(t *testing.T) {
+	arc := NewARCCache(3).(*ARC)
+	arc.Add("a", 1)
+	assert.Equal(t, 1, arc.t1.Len())
+
+	arc.Get("a")
+	assert.Equal(t, 0, arc.t1.Len())
+	assert.Equal(t, 1, arc.t2.Len(), "Second access should move the key to T2")
+}
+
+// TestARC_AdaptP проверяет рост p при попадании в B1 и уменьшение при попадании в B2
+func TestARC_AdaptP(t *testing.T) {
+	arc := NewARCCache(2).(*ARC)
+	arc.Add("a", 1)
+	arc.Add("b", 2)
+	arc.Get("a")    // T1=[b], T2=[a]
+	arc.Add("c", 3) // b уходит в B1
+
+	_, ok := arc.Get("b")
+	assert.False(t, ok, "b should be a ghost")
+	assert.Equal(t, 1, arc.b1.Len())
+	assert.Equal(t, 0, arc.p)
+
+	arc.Add("b", 2) // попадание в B1
+	assert.Equal(t, 1, arc.p, "Hit in B1 should grow p")
+	assert.Equal(t, 1, arc.b2.Len(), "a should be demoted to B2")
+
+	arc.Add("a", 1) // попадание в B2
+	assert.Equal(t, 0, arc.p, "Hit in B2 should shrink p")
+
+	val, ok := arc.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+}
+
+// TestARC_SizeBounds проверяет, что резидентный размер и призрачные списки ограничены
+func TestARC_SizeBounds(t *testing.T) {
+	const capacity = 8
+	arc := NewARCCache(capacity).(*ARC)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(40)
+		if rng.Intn(3) == 0 {
+			arc.Get(key)
+		} else {
+			arc.Add(key, i)
+		}
+
+		assert.LessOrEqual(t, arc.Len(), capacity, "Resident size should not exceed capacity")
+		assert.LessOrEqual(t, arc.t1.Len()+arc.b1.Len(), capacity)
+		assert.LessOrEqual(t, arc.Len()+arc.b1.Len()+arc.b2.Len(), 2*capacity)
+		assert.Equal(t, arc.Len()+arc.b1.Len()+arc.b2.Len(), len(arc.items))
+		assert.GreaterOrEqual(t, arc.p, 0)
+		assert.LessOrEqual(t, arc.p, capacity)
+	}
+}
@@ -0,0 +1,66 @@
+// Package benchutil воспроизводит типовые последовательности обращений к кэшу,
+// чтобы сравнивать стратегии на одинаковой нагрузке
+package benchutil
+
+import (
+	"LRU_cache/pkg/cache"
+	"math/rand"
+)
+
+// Result - количество попаданий и промахов при воспроизведении трассы
+type Result struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRatio возвращает долю попаданий, 0 для пустой трассы
+func (r Result) HitRatio() float64 {
+	if r.Hits+r.Misses == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(r.Hits+r.Misses)
+}
+
+// Replay по очереди запрашивает ключи трассы через Get, а при промахе добавляет ключ в кэш,
+// как это делает кэширующий клиент. Попадания и промахи считаются по результатам Get,
+// поэтому подходят любые реализации cache.Cache
+func Replay(c cache.Cache, trace []interface{}) Result {
+	var result Result
+	for _, key := range trace {
+		if _, ok := c.Get(key); ok {
+			result.Hits++
+			continue
+		}
+		result.Misses++
+		c.Add(key, key)
+	}
+	return result
+}
+
+// Zipf возвращает трассу из n обращений к ключам 0..keys-1 с распределением Ципфа с параметром s > 1:
+// ключ 0 самый популярный. При одинаковом seed трасса одна и та же
+func Zipf(n int, keys uint64, s float64, seed int64) []interface{} {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(seed)), s, 1, keys-1)
+	trace := make([]interface{}, n)
+	for i := range trace {
+		trace[i] = int(zipf.Uint64())
+	}
+	return trace
+}
+
+// Sequential возвращает однократный проход по ключам 0..n-1 без повторов
+func Sequential(n int) []interface{} {
+	return Loop(n, n)
+}
+
+// Loop возвращает n обращений, циклически перебирающих ключи 0..period-1
+func Loop(n, period int) []interface{} {
+	if period <= 0 {
+		panic("period must be positive")
+	}
+	trace := make([]interface{}, n)
+	for i := range trace {
+		trace[i] = i % period
+	}
+	return trace
+}
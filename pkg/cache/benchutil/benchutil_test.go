@@ -0,0 +1,44 @@
+package benchutil
+
+import (
+	"LRU_cache/pkg/cache/lfu"
+	"LRU_cache/pkg/cache/lru"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReplay_Counts проверяет подсчёт попаданий и промахов на простых трассах
+func TestReplay_Counts(t *testing.T) {
+	result := Replay(lru.NewLRUCache(10), Sequential(20))
+	assert.Equal(t, Result{Hits: 0, Misses: 20}, result, "A scan never hits")
+
+	result = Replay(lru.NewLRUCache(5), Loop(20, 5))
+	assert.Equal(t, Result{Hits: 15, Misses: 5}, result, "A loop that fits hits after the first pass")
+
+	result = Replay(lru.NewLRUCache(4), Loop(20, 5))
+	assert.Equal(t, uint64(0), result.Hits, "LRU always misses on a loop one key larger than the cache")
+
+	assert.Equal(t, 0.75, Result{Hits: 3, Misses: 1}.HitRatio())
+	assert.Equal(t, 0.0, Result{}.HitRatio())
+}
+
+// TestZipf_Deterministic проверяет воспроизводимость и диапазон ключей трассы Ципфа
+func TestZipf_Deterministic(t *testing.T) {
+	trace := Zipf(1000, 100, 1.2, 7)
+	assert.Equal(t, trace, Zipf(1000, 100, 1.2, 7))
+	for _, key := range trace {
+		assert.GreaterOrEqual(t, key.(int), 0)
+		assert.Less(t, key.(int), 100)
+	}
+}
+
+// TestReplay_LFUBeatsLRUOnZipf сравнивает LRU и LFU на одной трассе Ципфа с фиксированным seed
+func TestReplay_LFUBeatsLRUOnZipf(t *testing.T) {
+	trace := Zipf(100000, 10000, 1.1, 42)
+
+	lruResult := Replay(lru.NewLRUCache(100), trace)
+	lfuResult := Replay(lfu.NewLFUCache(100), trace)
+	t.Logf("LRU hit ratio %.3f, LFU hit ratio %.3f", lruResult.HitRatio(), lfuResult.HitRatio())
+	assert.Greater(t, lfuResult.HitRatio(), lruResult.HitRatio(), "LFU should win on a stable Zipf distribution")
+}
@@ -2,7 +2,7 @@ package cache
 
 type Cache interface {
 	// Add Добавляет новое значение с ключом в кеш (с наивысшим приоритетом), возвращает true, если все прошло успешно
-	// В случае дублирования ключа вернуть false
+	// В случае дублирования ключа значение обновляется, элемент получает наивысший приоритет, возвращается false
 	// В случае превышения размера - вытесняется наименее приоритетный элемент
 	Add(key, value interface{}) bool
 
@@ -12,4 +12,29 @@ type Cache interface {
 
 	// Remove Удаляет элемент из кеша, в случае успеха возврашает true, в случае отсутствия элемента - false
 	Remove(key interface{}) (ok bool)
+
+	// Len Возвращает текущее количество элементов в кеше
+	Len() int
+
+	// Clear Удаляет из кеша все элементы
+	Clear()
+}
+
+// KV - пара ключ-значение, извлечённая из кеша
+type KV struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// Entry - снимок элемента кеша с рангом, который задаёт политика вытеснения
+// (например, частота для LFU или позиция по давности обращения для LRU)
+type Entry struct {
+	Key   interface{}
+	Value interface{}
+	Rank  int
+}
+
+// EntryLister - кеш, который отдаёт снимок своих элементов в порядке вытеснения (например, *lru.LRU и *lfu.LFUCache)
+type EntryLister interface {
+	Entries() []Entry
 }
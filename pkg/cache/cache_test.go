@@ -0,0 +1,69 @@
+package cache_test
+
+import (
+	"LRU_cache/pkg/cache"
+	"LRU_cache/pkg/cache/actor"
+	"LRU_cache/pkg/cache/arc"
+	"LRU_cache/pkg/cache/car"
+	"LRU_cache/pkg/cache/clock"
+	"LRU_cache/pkg/cache/fifo"
+	"LRU_cache/pkg/cache/gdsf"
+	"LRU_cache/pkg/cache/hyperbolic"
+	"LRU_cache/pkg/cache/lfu"
+	"LRU_cache/pkg/cache/lru"
+	"LRU_cache/pkg/cache/lruk"
+	"LRU_cache/pkg/cache/random"
+	"LRU_cache/pkg/cache/s3fifo"
+	"LRU_cache/pkg/cache/sharded"
+	"LRU_cache/pkg/cache/sieve"
+	"LRU_cache/pkg/cache/slru"
+	"LRU_cache/pkg/cache/tiered"
+	"LRU_cache/pkg/cache/twoq"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест: Len и Clear одинаково работают у всех реализаций cache.Cache
+func TestCache_LenAndClear(t *testing.T) {
+	actorCache := actor.NewActorCache(lru.NewLRUCache(10))
+	defer actorCache.(*actor.ActorCache).Close()
+
+	caches := map[string]cache.Cache{
+		"lru":        lru.NewLRUCache(10),
+		"lfu":        lfu.NewLFUCache(10),
+		"fifo":       fifo.NewFIFOCache(10),
+		"arc":        arc.NewARCCache(10),
+		"car":        car.NewCARCache(10),
+		"slru":       slru.NewSLRUCache(10),
+		"twoq":       twoq.NewTwoQCache(10),
+		"clock":      clock.NewClockCache(10),
+		"sieve":      sieve.NewSieveCache(10),
+		"s3fifo":     s3fifo.NewS3FIFOCache(10),
+		"lruk":       lruk.NewLRUKCache(10, 2),
+		"gdsf":       gdsf.NewGDSFCache(10),
+		"random":     random.NewRandomCache(10, 1),
+		"hyperbolic": hyperbolic.NewHyperbolicCache(10, 1),
+		"sharded":    sharded.NewShardedCache(10, 2, func(capacity int) cache.Cache { return lru.NewLRUCache(capacity) }),
+		"actor":      actorCache,
+		"tiered":     tiered.NewTieredCache(lfu.NewLFUCache(2), lru.NewLRUCache(10)),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, 0, c.Len())
+			c.Add("a", 1)
+			c.Add("b", 2)
+			c.Add("c", 3)
+			assert.Equal(t, 3, c.Len())
+
+			c.Clear()
+			assert.Equal(t, 0, c.Len())
+			_, ok := c.Get("a")
+			assert.False(t, ok, "Cleared cache should miss")
+
+			assert.True(t, c.Add("a", 1), "Cleared cache should accept new keys")
+			assert.Equal(t, 1, c.Len())
+		})
+	}
+}
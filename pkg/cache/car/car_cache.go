@@ -0,0 +1,192 @@
+package car
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/list"
+)
+
+// entry - элемент одного из четырёх списков CAR. У призрачных записей (B1, B2) значение не хранится
+type entry struct {
+	key        interface{}
+	value      interface{}
+	referenced bool       // бит обращения, выставляется при попадании
+	list       *list.List // список, в котором сейчас находится элемент
+}
+
+// CAR - Clock with Adaptive Replacement. Как и в ARC, резидентные элементы делятся на T1 (встречались
+// один раз) и T2 (встречались повторно), а B1 и B2 - призрачные списки вытесненных из них ключей.
+// T1 и T2 устроены как часы: попадание только выставляет бит обращения, а стрелка (начало списка)
+// при вытеснении переносит элементы с выставленным битом в конец T2. Целевой размер T1 (p)
+// подстраивается при попаданиях в призрачные списки так же, как в ARC
+type CAR struct {
+	capacity int
+	p        int // целевой размер T1
+
+	t1, t2 *list.List // часы резидентных элементов, стрелка указывает на начало списка
+	b1, b2 *list.List // призрачные ключи, в начале самые недавно вытесненные
+
+	items map[interface{}]*list.Element // ключ -> элемент в одном из четырёх списков
+}
+
+// NewCARCache создает CAR кэш на n резидентных элементов; призрачные списки хранят ещё не более n ключей
+func NewCARCache(n int) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	return &CAR{
+		capacity: n,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[interface{}]*list.Element),
+	}
+}
+
+// Add добавляет значение. Существующий резидентный ключ обновляется, получает бит обращения и возвращается false.
+// Ключ из призрачного списка сдвигает целевой размер p и попадает в конец T2
+func (c *CAR) Add(key, value interface{}) bool {
+	element, exists := c.items[key]
+	if exists {
+		e := element.Value.(*entry)
+		if e.list == c.t1 || e.list == c.t2 {
+			e.value = value
+			e.referenced = true
+			return false
+		}
+	}
+
+	if c.t1.Len()+c.t2.Len() == c.capacity {
+		c.replace()
+		if !exists {
+			if c.t1.Len()+c.b1.Len() == c.capacity {
+				c.removeBack(c.b1)
+			} else if c.t1.Len()+c.t2.Len()+c.b1.Len()+c.b2.Len() == 2*c.capacity {
+				c.removeBack(c.b2)
+			}
+		}
+	}
+
+	if !exists {
+		c.items[key] = c.t1.PushBack(&entry{key: key, value: value, list: c.t1})
+		return true
+	}
+
+	e := element.Value.(*entry)
+	if e.list == c.b1 {
+		c.p = minInt(c.capacity, c.p+maxInt(c.b2.Len()/c.b1.Len(), 1))
+	} else {
+		c.p = maxInt(0, c.p-maxInt(c.b1.Len()/c.b2.Len(), 1))
+	}
+	e.value = value
+	e.referenced = false
+	c.moveToBack(element, c.t2)
+	return true
+}
+
+// Get возвращает резидентное значение; попадание только выставляет бит обращения
+func (c *CAR) Get(key interface{}) (value interface{}, ok bool) {
+	element, exists := c.items[key]
+	if !exists {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if e.list != c.t1 && e.list != c.t2 {
+		return nil, false
+	}
+	e.referenced = true
+	return e.value, true
+}
+
+// Remove удаляет резидентный элемент. Призрачная запись для ключа тоже забывается, но не считается удалением
+func (c *CAR) Remove(key interface{}) (ok bool) {
+	element, exists := c.items[key]
+	if !exists {
+		return false
+	}
+	e := element.Value.(*entry)
+	e.list.Remove(element)
+	delete(c.items, key)
+	return e.list == c.t1 || e.list == c.t2
+}
+
+// Len возвращает количество резидентных элементов
+func (c *CAR) Len() int {
+	return c.t1.Len() + c.t2.Len()
+}
+
+// Clear удаляет резидентные элементы и призрачные ключи и сбрасывает адаптацию p
+func (c *CAR) Clear() {
+	c.p = 0
+	c.t1.Init()
+	c.t2.Init()
+	c.b1.Init()
+	c.b2.Init()
+	c.items = make(map[interface{}]*list.Element)
+}
+
+// replace двигает стрелки часов, пока не вытеснит в призрачный список элемент без бита обращения.
+// Элементы с выставленным битом сбрасывают его и переходят в конец T2
+func (c *CAR) replace() {
+	for {
+		if c.t1.Len() >= maxInt(1, c.p) {
+			head := c.t1.Front()
+			e := head.Value.(*entry)
+			if !e.referenced {
+				c.demote(head, c.b1)
+				return
+			}
+			e.referenced = false
+			c.moveToBack(head, c.t2)
+		} else {
+			head := c.t2.Front()
+			e := head.Value.(*entry)
+			if !e.referenced {
+				c.demote(head, c.b2)
+				return
+			}
+			e.referenced = false
+			c.t2.MoveToBack(head)
+		}
+	}
+}
+
+// demote переносит элемент в начало призрачного списка, освобождая значение
+func (c *CAR) demote(element *list.Element, ghost *list.List) {
+	e := element.Value.(*entry)
+	e.value = nil
+	e.referenced = false
+	e.list.Remove(element)
+	e.list = ghost
+	c.items[e.key] = ghost.PushFront(e)
+}
+
+// moveToBack переносит элемент в конец часов target, то есть как можно дальше от стрелки
+func (c *CAR) moveToBack(element *list.Element, target *list.List) {
+	e := element.Value.(*entry)
+	e.list.Remove(element)
+	e.list = target
+	c.items[e.key] = target.PushBack(e)
+}
+
+// removeBack полностью удаляет самый старый ключ призрачного списка
+func (c *CAR) removeBack(l *list.List) {
+	if element := l.Back(); element != nil {
+		e := l.Remove(element).(*entry)
+		delete(c.items, e.key)
+	}
+}
+
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
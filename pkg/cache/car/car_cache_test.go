@@ -0,0 +1,93 @@
+package car
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCAR_Basic проверяет базовые операции интерфейса
+func TestCAR_Basic(t *testing.T) {
+	car := NewCARCache(2).(*CAR)
+
+	assert.True(t, car.Add("a", 1))
+	assert.False(t, car.Add("a", 10), "Existing key should return false")
+
+	val, ok := car.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = car.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, car.Remove("a"))
+	assert.False(t, car.Remove("a"))
+	assert.Equal(t, 0, car.Len())
+}
+
+// TestCAR_ReferenceBit проверяет, что попадание не двигает элемент, а при вытеснении он переходит в T2
+func TestCAR_ReferenceBit(t *testing.T) {
+	car := NewCARCache(2).(*CAR)
+	car.Add("a", 1)
+	car.Add("b", 2)
+
+	car.Get("a")
+	assert.Equal(t, 2, car.t1.Len(), "Hit should only set the reference bit")
+	assert.Equal(t, "a", car.t1.Front().Value.(*entry).key)
+
+	car.Add("c", 3)
+	assert.Equal(t, 1, car.t2.Len(), "Referenced key should move to T2 on sweep")
+	_, ok := car.Get("a")
+	assert.True(t, ok)
+	_, ok = car.Get("b")
+	assert.False(t, ok, "Unreferenced key should be demoted")
+	assert.Equal(t, 1, car.b1.Len())
+}
+
+// TestCAR_AdaptP проверяет рост p при попадании в B1 и уменьшение при попадании в B2
+func TestCAR_AdaptP(t *testing.T) {
+	car := NewCARCache(2).(*CAR)
+	car.Add("a", 1)
+	car.Add("b", 2)
+	car.Get("a")
+	car.Add("c", 3) // a переходит в T2, b уходит в B1
+	assert.Equal(t, 0, car.p)
+
+	car.Add("b", 2) // попадание в B1
+	assert.Equal(t, 1, car.p, "Hit in B1 should grow p")
+
+	car.Add("d", 4) // a уходит в B2
+	assert.Equal(t, 1, car.b2.Len())
+
+	car.Add("a", 1) // попадание в B2
+	assert.Equal(t, 0, car.p, "Hit in B2 should shrink p")
+
+	val, ok := car.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+}
+
+// TestCAR_SizeBounds проверяет, что резидентный размер и призрачные списки ограничены
+func TestCAR_SizeBounds(t *testing.T) {
+	const capacity = 8
+	car := NewCARCache(capacity).(*CAR)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(40)
+		if rng.Intn(3) == 0 {
+			car.Get(key)
+		} else {
+			car.Add(key, i)
+		}
+
+		assert.LessOrEqual(t, car.Len(), capacity, "Resident size should not exceed capacity")
+		assert.LessOrEqual(t, car.t1.Len()+car.b1.Len(), capacity)
+		assert.LessOrEqual(t, car.Len()+car.b1.Len()+car.b2.Len(), 2*capacity)
+		assert.Equal(t, car.Len()+car.b1.Len()+car.b2.Len(), len(car.items))
+		assert.GreaterOrEqual(t, car.p, 0)
+		assert.LessOrEqual(t, car.p, capacity)
+	}
+}
@@ -0,0 +1,113 @@
+package clock
+
+import (
+	"LRU_cache/pkg/cache"
+)
+
+// slot - ячейка кольцевого буфера
+type slot struct {
+	key        interface{}
+	value      interface{}
+	referenced bool // бит обращения, выставляется в Get
+	occupied   bool
+}
+
+// Clock - кэш «второго шанса»: элементы лежат в кольцевом буфере, Get выставляет бит обращения,
+// а стрелка при вытеснении сбрасывает биты, пока не найдёт элемент без обращений
+type Clock struct {
+	slots []slot
+	items map[interface{}]int // ключ -> индекс ячейки
+	free  []int               // индексы освободившихся после Remove ячеек
+	hand  int                 // позиция стрелки
+	used  int                 // число ячеек, занятых хотя бы раз
+}
+
+// NewClockCache создает CLOCK кэш на n элементов
+func NewClockCache(n int) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	return &Clock{
+		slots: make([]slot, n),
+		items: make(map[interface{}]int, n),
+	}
+}
+
+// Add добавляет значение. Для существующего ключа обновляет значение, выставляет бит обращения и возвращает false
+func (c *Clock) Add(key, value interface{}) bool {
+	if idx, ok := c.items[key]; ok {
+		c.slots[idx].value = value
+		c.slots[idx].referenced = true
+		return false
+	}
+
+	idx := c.freeSlot()
+	c.slots[idx] = slot{key: key, value: value, occupied: true}
+	c.items[key] = idx
+	return true
+}
+
+// Get возвращает значение и выставляет бит обращения, не перемещая элемент
+func (c *Clock) Get(key interface{}) (value interface{}, ok bool) {
+	idx, exists := c.items[key]
+	if !exists {
+		return nil, false
+	}
+	c.slots[idx].referenced = true
+	return c.slots[idx].value, true
+}
+
+func (c *Clock) Remove(key interface{}) (ok bool) {
+	idx, exists := c.items[key]
+	if !exists {
+		return false
+	}
+	c.slots[idx] = slot{}
+	delete(c.items, key)
+	c.free = append(c.free, idx)
+	return true
+}
+
+func (c *Clock) Len() int {
+	return len(c.items)
+}
+
+// Clear освобождает все ячейки и возвращает стрелку в начало
+func (c *Clock) Clear() {
+	c.slots = make([]slot, len(c.slots))
+	c.items = make(map[interface{}]int, len(c.slots))
+	c.free = nil
+	c.hand = 0
+	c.used = 0
+}
+
+// freeSlot возвращает индекс ячейки под новый элемент, при необходимости вытесняя элемент стрелкой
+func (c *Clock) freeSlot() int {
+	if n := len(c.free); n > 0 {
+		idx := c.free[n-1]
+		c.free = c.free[:n-1]
+		return idx
+	}
+	if c.used < len(c.slots) {
+		c.used++
+		return c.used - 1
+	}
+	return c.evict()
+}
+
+// evict двигает стрелку, сбрасывая биты обращения, до первого элемента без обращений и освобождает его ячейку
+func (c *Clock) evict() int {
+	for {
+		s := &c.slots[c.hand]
+		idx := c.hand
+		c.hand = (c.hand + 1) % len(c.slots)
+
+		if s.referenced {
+			s.referenced = false
+			continue
+		}
+		delete(c.items, s.key)
+		*s = slot{}
+		return idx
+	}
+}
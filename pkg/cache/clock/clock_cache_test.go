@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClock_Basic проверяет базовые операции интерфейса
+func TestClock_Basic(t *testing.T) {
+	c := NewClockCache(2).(*Clock)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = c.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.Len())
+}
+
+// TestClock_SecondChance проверяет, что элемент с обращением переживает один круг вытеснения
+func TestClock_SecondChance(t *testing.T) {
+	c := NewClockCache(3).(*Clock)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	c.Get("a") // a получает второй шанс
+
+	c.Add("d", 4) // стрелка сбрасывает бит a и вытесняет b
+	_, ok := c.Get("b")
+	assert.False(t, ok, "b should be evicted")
+	_, ok = c.Get("a")
+	assert.True(t, ok, "a touched since the last sweep should survive")
+
+	c.Add("e", 5) // a снова помечен этим Get, вытесняется c
+	_, ok = c.Get("c")
+	assert.False(t, ok, "c should be evicted next")
+	assert.Equal(t, 3, c.Len())
+}
+
+// TestClock_AllReferenced проверяет полный оборот стрелки, когда у всех элементов выставлен бит
+func TestClock_AllReferenced(t *testing.T) {
+	c := NewClockCache(2).(*Clock)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Get("a")
+	c.Get("b")
+
+	c.Add("c", 3) // после сброса всех битов вытесняется a
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+// TestClock_ReuseRemovedSlot проверяет повторное использование ячейки после Remove
+func TestClock_ReuseRemovedSlot(t *testing.T) {
+	c := NewClockCache(2).(*Clock)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Remove("a")
+
+	c.Add("c", 3) // свободная ячейка, без вытеснения
+	_, ok := c.Get("b")
+	assert.True(t, ok, "b should not be evicted while a free slot exists")
+	assert.Equal(t, 2, c.Len())
+}
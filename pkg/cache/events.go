@@ -0,0 +1,33 @@
+package cache
+
+// EvictionReason - причина, по которой элемент покинул кеш
+type EvictionReason int
+
+const (
+	// EvictedCapacity - элемент вытеснен политикой, чтобы освободить место
+	EvictedCapacity EvictionReason = iota
+	// EvictedManual - элемент удалён явным вызовом (Remove, EvictN и т.п.)
+	EvictedManual
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedManual:
+		return "manual"
+	default:
+		return "unknown"
+	}
+}
+
+// EvictedEntry - событие об удалённом из кеша элементе
+type EvictedEntry struct {
+	Key    interface{}
+	Value  interface{}
+	Reason EvictionReason
+}
+
+// EventBufferSize - размер буфера канала событий удаления. Если потребитель не успевает
+// и буфер заполнен, новые события отбрасываются, чтобы не блокировать кеш
+const EventBufferSize = 64
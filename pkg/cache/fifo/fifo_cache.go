@@ -0,0 +1,89 @@
+package fifo
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/list"
+)
+
+type Item struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// FIFO вытесняет самый давно добавленный элемент; обращения к элементам порядок не меняют
+type FIFO struct {
+	capacity int
+	items    map[interface{}]*list.Element
+	queue    *list.List // в начале самые новые элементы, в конце - кандидат на вытеснение
+}
+
+// Add добавляет новый элемент в начало очереди. Для существующего ключа обновляет значение
+// без изменения его позиции и возвращает false
+func (f *FIFO) Add(key, value interface{}) bool {
+	if element, exists := f.items[key]; exists {
+		element.Value.(*Item).Value = value
+		return false
+	}
+
+	if f.capacity == 0 {
+		return true
+	}
+
+	if f.queue.Len() == f.capacity {
+		f.removeOldest()
+	}
+
+	item := &Item{
+		Key:   key,
+		Value: value,
+	}
+	f.items[key] = f.queue.PushFront(item)
+
+	return true
+}
+
+// Get возвращает значение без изменения порядка вытеснения
+func (f *FIFO) Get(key interface{}) (value interface{}, ok bool) {
+	element, exists := f.items[key]
+	if !exists {
+		return nil, false
+	}
+	return element.Value.(*Item).Value, true
+}
+
+func (f *FIFO) Remove(key interface{}) (ok bool) {
+	element, exists := f.items[key]
+	if !exists {
+		return false
+	}
+	f.queue.Remove(element)
+	delete(f.items, key)
+	return true
+}
+
+func (f *FIFO) Len() int {
+	return f.queue.Len()
+}
+
+func (f *FIFO) Clear() {
+	f.items = make(map[interface{}]*list.Element)
+	f.queue.Init()
+}
+
+func (f *FIFO) removeOldest() {
+	if element := f.queue.Back(); element != nil {
+		item := f.queue.Remove(element).(*Item)
+		delete(f.items, item.Key)
+	}
+}
+
+func NewFIFOCache(n int) cache.Cache {
+	if n < 0 {
+		panic("capacity must not be negative")
+	}
+	return &FIFO{
+		capacity: n,
+		items:    make(map[interface{}]*list.Element),
+		queue:    list.New(),
+	}
+}
@@ -0,0 +1,73 @@
+package fifo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест: вытесняется самый давно добавленный элемент независимо от обращений
+func TestFIFO_EvictionIgnoresAccess(t *testing.T) {
+	fifo := NewFIFOCache(3).(*FIFO)
+	fifo.Add("a", 1)
+	fifo.Add("b", 2)
+	fifo.Add("c", 3)
+
+	// Многократные обращения к a не спасают его от вытеснения
+	fifo.Get("a")
+	fifo.Get("a")
+	fifo.Get("a")
+
+	fifo.Add("d", 4)
+	_, ok := fifo.Get("a")
+	assert.False(t, ok, "a should be evicted first as the oldest")
+
+	fifo.Add("e", 5)
+	_, ok = fifo.Get("b")
+	assert.False(t, ok, "b should be evicted next")
+
+	for _, key := range []string{"c", "d", "e"} {
+		_, ok := fifo.Get(key)
+		assert.True(t, ok, "%s should remain", key)
+	}
+	assert.Equal(t, 3, fifo.Len())
+}
+
+// Тест: повторное добавление обновляет значение, но не продлевает жизнь элемента
+func TestFIFO_Add_ExistingKey(t *testing.T) {
+	fifo := NewFIFOCache(2).(*FIFO)
+	fifo.Add("a", 1)
+	fifo.Add("b", 2)
+
+	ok := fifo.Add("a", 10)
+	assert.False(t, ok, "Existing key should return false")
+	val, _ := fifo.Get("a")
+	assert.Equal(t, 10, val, "Value should be updated")
+
+	fifo.Add("c", 3)
+	_, ok = fifo.Get("a")
+	assert.False(t, ok, "Update should not change insertion order")
+}
+
+// Тест: Remove и промах
+func TestFIFO_Remove(t *testing.T) {
+	fifo := NewFIFOCache(2).(*FIFO)
+	fifo.Add("a", 1)
+
+	assert.True(t, fifo.Remove("a"))
+	assert.False(t, fifo.Remove("a"))
+
+	val, ok := fifo.Get("a")
+	assert.Nil(t, val)
+	assert.False(t, ok)
+	assert.Equal(t, 0, fifo.Len())
+}
+
+// Тест: ёмкость 0 ничего не хранит
+func TestFIFO_ZeroCapacity(t *testing.T) {
+	fifo := NewFIFOCache(0).(*FIFO)
+
+	assert.True(t, fifo.Add("a", 1))
+	_, ok := fifo.Get("a")
+	assert.False(t, ok)
+}
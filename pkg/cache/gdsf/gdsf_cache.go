@@ -0,0 +1,182 @@
+package gdsf
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/heap"
+)
+
+// entry - элемент кэша с размером, частотой и приоритетом
+type entry struct {
+	key       interface{}
+	value     interface{}
+	size      int64
+	frequency int
+	priority  float64
+	seq       uint64 // момент последнего обращения, разрешает равенство приоритетов в пользу LRU
+	index     int    // позиция в куче
+}
+
+// GDSF - кэш Greedy-Dual-Size-Frequency, ограниченный суммарным размером элементов.
+// Приоритет элемента равен значению часов инфляции плюс частота, делённая на размер.
+// Вытесняется элемент с наименьшим приоритетом, а часы поднимаются до его приоритета,
+// поэтому давно не используемые элементы со временем уступают место новым.
+// Элементы хранятся в куче по приоритету, Add и Get выполняются за O(log n)
+type GDSF struct {
+	maxSize   int64
+	totalSize int64
+	clock     float64 // часы инфляции L
+	seq       uint64
+	items     map[interface{}]*entry
+	queue     priorityQueue
+}
+
+// NewGDSFCache создает кэш с ограничением суммарного размера maxSize.
+// Элементы, добавленные через Add, имеют размер 1
+func NewGDSFCache(maxSize int64) cache.Cache {
+	if maxSize <= 0 {
+		panic("max size must be positive")
+	}
+	return &GDSF{
+		maxSize: maxSize,
+		items:   make(map[interface{}]*entry),
+	}
+}
+
+// Add работает как AddWithSize с размером 1
+func (g *GDSF) Add(key, value interface{}) bool {
+	return g.AddWithSize(key, value, 1)
+}
+
+// AddWithSize добавляет значение размера size, при переполнении вытесняя элементы с наименьшим приоритетом.
+// Для существующего ключа обновляет значение и размер, засчитывает обращение и возвращает false.
+// Элемент больше maxSize отклоняется целиком: возвращается false, кэш не меняется
+func (g *GDSF) AddWithSize(key, value interface{}, size int64) bool {
+	if size <= 0 {
+		panic("size must be positive")
+	}
+	if size > g.maxSize {
+		return false
+	}
+
+	if e, ok := g.items[key]; ok {
+		g.totalSize += size - e.size
+		e.value = value
+		e.size = size
+		g.touch(e)
+		g.evictOverSize(e)
+		return false
+	}
+
+	g.totalSize += size
+	g.evictOverSize(nil)
+	e := &entry{key: key, value: value, size: size, index: -1}
+	g.touch(e)
+	heap.Push(&g.queue, e)
+	g.items[key] = e
+	return true
+}
+
+// Get возвращает значение и повышает частоту элемента
+func (g *GDSF) Get(key interface{}) (value interface{}, ok bool) {
+	e, exists := g.items[key]
+	if !exists {
+		return nil, false
+	}
+	g.touch(e)
+	return e.value, true
+}
+
+func (g *GDSF) Remove(key interface{}) (ok bool) {
+	e, exists := g.items[key]
+	if !exists {
+		return false
+	}
+	heap.Remove(&g.queue, e.index)
+	g.remove(e)
+	return true
+}
+
+func (g *GDSF) Len() int {
+	return len(g.items)
+}
+
+// Size возвращает суммарный размер элементов в кэше
+func (g *GDSF) Size() int64 {
+	return g.totalSize
+}
+
+// Clear удаляет все элементы и сбрасывает часы инфляции
+func (g *GDSF) Clear() {
+	g.items = make(map[interface{}]*entry)
+	g.queue = nil
+	g.totalSize = 0
+	g.clock = 0
+}
+
+// touch засчитывает обращение и пересчитывает приоритет элемента
+func (g *GDSF) touch(e *entry) {
+	g.seq++
+	e.seq = g.seq
+	e.frequency++
+	e.priority = g.clock + float64(e.frequency)/float64(e.size)
+	if e.index >= 0 {
+		heap.Fix(&g.queue, e.index)
+	}
+}
+
+// evictOverSize вытесняет элементы с наименьшим приоритетом, кроме keep, пока суммарный размер превышает maxSize
+func (g *GDSF) evictOverSize(keep *entry) {
+	kept := false
+	for g.totalSize > g.maxSize {
+		e := heap.Pop(&g.queue).(*entry)
+		if e == keep {
+			// keep сам по себе помещается в кэш, значит, в куче есть другие элементы
+			kept = true
+			continue
+		}
+		g.clock = e.priority
+		g.remove(e)
+	}
+	if kept {
+		heap.Push(&g.queue, keep)
+	}
+}
+
+// remove удаляет уже извлечённый из кучи элемент из индекса
+func (g *GDSF) remove(e *entry) {
+	delete(g.items, e.key)
+	g.totalSize -= e.size
+}
+
+// priorityQueue - куча элементов по возрастанию приоритета
+type priorityQueue []*entry
+
+func (q priorityQueue) Len() int { return len(q) }
+
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q priorityQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *priorityQueue) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*q = old[:len(old)-1]
+	return e
+}
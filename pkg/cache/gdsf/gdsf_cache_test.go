@@ -0,0 +1,81 @@
+package gdsf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGDSF_Basic проверяет базовые операции интерфейса
+func TestGDSF_Basic(t *testing.T) {
+	c := NewGDSFCache(2).(*GDSF)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, int64(0), c.Size())
+}
+
+// TestGDSF_LargerEvictedFirst проверяет, что из одинаково частых элементов первым вытесняется больший
+func TestGDSF_LargerEvictedFirst(t *testing.T) {
+	c := NewGDSFCache(10).(*GDSF)
+
+	c.AddWithSize("large", 1, 6)
+	c.AddWithSize("small", 2, 2)
+	c.AddWithSize("new", 3, 4)
+
+	_, ok := c.Get("large")
+	assert.False(t, ok, "Larger entry should be evicted first")
+	_, ok = c.Get("small")
+	assert.True(t, ok)
+	_, ok = c.Get("new")
+	assert.True(t, ok)
+	assert.Equal(t, int64(6), c.Size())
+}
+
+// TestGDSF_FrequencyBoostsRetention проверяет, что частые обращения удерживают элемент того же размера
+func TestGDSF_FrequencyBoostsRetention(t *testing.T) {
+	c := NewGDSFCache(10).(*GDSF)
+
+	c.AddWithSize("hot", 1, 5)
+	c.AddWithSize("cold", 2, 5)
+	for i := 0; i < 3; i++ {
+		c.Get("hot")
+	}
+
+	c.AddWithSize("next", 3, 5)
+	_, ok := c.Get("cold")
+	assert.False(t, ok, "Rarely used entry should be evicted")
+	_, ok = c.Get("hot")
+	assert.True(t, ok, "Frequently used entry should survive")
+	assert.Greater(t, c.clock, 0.0, "Eviction should advance the inflation clock")
+}
+
+// TestGDSF_SizeLimits проверяет отклонение слишком больших элементов и изменение размера существующего
+func TestGDSF_SizeLimits(t *testing.T) {
+	c := NewGDSFCache(10).(*GDSF)
+
+	assert.False(t, c.AddWithSize("huge", 1, 11), "Entry larger than max size should be rejected")
+	assert.Equal(t, 0, c.Len())
+
+	c.AddWithSize("a", 1, 4)
+	c.AddWithSize("b", 2, 4)
+	assert.False(t, c.AddWithSize("a", 10, 8), "Growing an entry should evict others, not itself")
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, int64(8), c.Size())
+
+	assert.Panics(t, func() { c.AddWithSize("zero", 1, 0) })
+}
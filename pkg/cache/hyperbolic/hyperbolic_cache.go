@@ -0,0 +1,146 @@
+package hyperbolic
+
+import (
+	"LRU_cache/pkg/cache"
+	"math/rand"
+)
+
+// DefaultSampleSize - число элементов, просматриваемых при вытеснении по умолчанию
+const DefaultSampleSize = 64
+
+// entry - элемент кэша со счётчиком обращений и моментом добавления
+type entry struct {
+	key      interface{}
+	value    interface{}
+	accesses uint64
+	inserted uint64 // значение логических часов при добавлении
+}
+
+// Hyperbolic - кэш с гиперболическим вытеснением: приоритет элемента равен числу обращений,
+// делённому на его возраст. Время логическое и увеличивается на каждом Add и Get.
+// При переполнении просматривается случайная выборка элементов и удаляется элемент
+// с наименьшим приоритетом, поэтому вытеснение стоит O(sampleSize), а не O(n)
+type Hyperbolic struct {
+	capacity   int
+	sampleSize int
+	entries    []entry
+	index      map[interface{}]int // ключ -> позиция в entries
+	clock      uint64
+	rng        *rand.Rand
+}
+
+// NewHyperbolicCache создает кэш на n элементов с выборкой DefaultSampleSize
+func NewHyperbolicCache(n int, seed int64) cache.Cache {
+	return NewHyperbolicCacheWithSample(n, DefaultSampleSize, seed)
+}
+
+// NewHyperbolicCacheWithSample создает кэш на n элементов, просматривающий при вытеснении
+// sampleSize случайных элементов. Если sampleSize не меньше размера кэша, просматриваются все
+func NewHyperbolicCacheWithSample(n, sampleSize int, seed int64) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	if sampleSize <= 0 {
+		panic("sample size must be positive")
+	}
+	return &Hyperbolic{
+		capacity:   n,
+		sampleSize: sampleSize,
+		entries:    make([]entry, 0, n),
+		index:      make(map[interface{}]int, n),
+		rng:        rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Add добавляет значение, при переполнении вытесняя элемент с наименьшим приоритетом.
+// Для существующего ключа обновляет значение, засчитывает обращение и возвращает false
+func (h *Hyperbolic) Add(key, value interface{}) bool {
+	h.clock++
+	if i, ok := h.index[key]; ok {
+		h.entries[i].value = value
+		h.entries[i].accesses++
+		return false
+	}
+
+	if len(h.entries) >= h.capacity {
+		h.removeAt(h.victim())
+	}
+	h.index[key] = len(h.entries)
+	h.entries = append(h.entries, entry{key: key, value: value, accesses: 1, inserted: h.clock})
+	return true
+}
+
+// Get возвращает значение и засчитывает обращение
+func (h *Hyperbolic) Get(key interface{}) (value interface{}, ok bool) {
+	h.clock++
+	i, exists := h.index[key]
+	if !exists {
+		return nil, false
+	}
+	h.entries[i].accesses++
+	return h.entries[i].value, true
+}
+
+func (h *Hyperbolic) Remove(key interface{}) (ok bool) {
+	i, exists := h.index[key]
+	if !exists {
+		return false
+	}
+	h.removeAt(i)
+	return true
+}
+
+func (h *Hyperbolic) Len() int {
+	return len(h.entries)
+}
+
+// Clear удаляет все элементы; логические часы и состояние генератора сохраняются
+func (h *Hyperbolic) Clear() {
+	h.entries = h.entries[:0]
+	h.index = make(map[interface{}]int, h.capacity)
+}
+
+// victim выбирает позицию элемента с наименьшим приоритетом среди выборки
+func (h *Hyperbolic) victim() int {
+	if h.sampleSize >= len(h.entries) {
+		best := 0
+		for i := 1; i < len(h.entries); i++ {
+			if h.less(i, best) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	best := h.rng.Intn(len(h.entries))
+	for n := 1; n < h.sampleSize; n++ {
+		if i := h.rng.Intn(len(h.entries)); h.less(i, best) {
+			best = i
+		}
+	}
+	return best
+}
+
+// less сообщает, что приоритет элемента i ниже приоритета элемента j.
+// Отношения accesses/age сравниваются перекрёстным умножением без деления
+func (h *Hyperbolic) less(i, j int) bool {
+	a, b := h.entries[i], h.entries[j]
+	return a.accesses*h.age(b) < b.accesses*h.age(a)
+}
+
+// age возвращает возраст элемента в тиках логических часов, не меньше 1
+func (h *Hyperbolic) age(e entry) uint64 {
+	return h.clock - e.inserted + 1
+}
+
+// removeAt удаляет элемент, переставляя на его место последний элемент среза
+func (h *Hyperbolic) removeAt(i int) {
+	last := len(h.entries) - 1
+	delete(h.index, h.entries[i].key)
+	if i != last {
+		h.entries[i] = h.entries[last]
+		h.index[h.entries[i].key] = i
+	}
+	h.entries[last] = entry{}
+	h.entries = h.entries[:last]
+}
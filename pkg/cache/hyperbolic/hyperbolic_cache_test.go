@@ -0,0 +1,69 @@
+package hyperbolic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHyperbolic_Basic проверяет базовые операции интерфейса
+func TestHyperbolic_Basic(t *testing.T) {
+	c := NewHyperbolicCache(2, 1)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.Len())
+}
+
+// TestHyperbolic_FrequentYoungOutlivesRareOld проверяет, что молодой часто используемый
+// элемент переживает старый редко используемый
+func TestHyperbolic_FrequentYoungOutlivesRareOld(t *testing.T) {
+	c := NewHyperbolicCacheWithSample(3, 3, 1)
+
+	c.Add("old", 1)
+	c.Get("old")
+	c.Add("filler", 2)
+	for i := 0; i < 5; i++ {
+		c.Get("filler")
+	}
+	c.Add("young", 3)
+	for i := 0; i < 10; i++ {
+		c.Get("young")
+	}
+
+	c.Add("new", 4)
+	_, ok := c.Get("old")
+	assert.False(t, ok, "Rarely accessed old entry should be evicted")
+	_, ok = c.Get("young")
+	assert.True(t, ok, "Frequently accessed young entry should survive")
+	_, ok = c.Get("new")
+	assert.True(t, ok)
+}
+
+// TestHyperbolic_Sampling проверяет, что при выборке меньше размера кэша ёмкость соблюдается,
+// а индекс согласован со срезом
+func TestHyperbolic_Sampling(t *testing.T) {
+	c := NewHyperbolicCacheWithSample(10, 2, 42).(*Hyperbolic)
+
+	for i := 0; i < 200; i++ {
+		c.Add(i, i)
+		c.Get(i % 7)
+		assert.LessOrEqual(t, c.Len(), 10)
+	}
+	assert.Equal(t, 10, c.Len())
+	for key, i := range c.index {
+		assert.Equal(t, key, c.entries[i].key)
+	}
+
+	assert.Panics(t, func() { NewHyperbolicCacheWithSample(10, 0, 1) })
+}
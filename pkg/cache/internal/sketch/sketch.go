@@ -0,0 +1,80 @@
+// Package sketch содержит приближённые счётчики частот для политик вытеснения и допуска
+package sketch
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// CountMinSketch - Count-Min Sketch: depth строк по width счётчиков. Ключ увеличивает по одному счётчику
+// в каждой строке, а оценкой частоты служит минимум этих счётчиков. Оценка никогда не бывает меньше
+// настоящего числа обращений и может превышать его только из-за коллизий.
+// Не безопасен для конкурентного использования
+type CountMinSketch struct {
+	rows  [][]uint64
+	width uint64
+}
+
+// New создает sketch из depth строк по width счётчиков
+func New(width, depth int) *CountMinSketch {
+	if width <= 0 || depth <= 0 {
+		panic("width and depth must be positive")
+	}
+	rows := make([][]uint64, depth)
+	for i := range rows {
+		rows[i] = make([]uint64, width)
+	}
+	return &CountMinSketch{rows: rows, width: uint64(width)}
+}
+
+// Increment учитывает одно обращение к ключу
+func (s *CountMinSketch) Increment(key interface{}) {
+	h1, h2 := hashes(key)
+	for i := range s.rows {
+		s.rows[i][s.index(h1, h2, i)]++
+	}
+}
+
+// Estimate возвращает оценку числа обращений к ключу
+func (s *CountMinSketch) Estimate(key interface{}) uint64 {
+	h1, h2 := hashes(key)
+	min := s.rows[0][s.index(h1, h2, 0)]
+	for i := 1; i < len(s.rows); i++ {
+		if c := s.rows[i][s.index(h1, h2, i)]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// Halve делит все счётчики пополам, чтобы старые обращения постепенно забывались
+func (s *CountMinSketch) Halve() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+}
+
+// Reset обнуляет все счётчики
+func (s *CountMinSketch) Reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] = 0
+		}
+	}
+}
+
+// index выбирает счётчик строки row двойным хешированием
+func (s *CountMinSketch) index(h1, h2 uint64, row int) uint64 {
+	return (h1 + uint64(row)*h2) % s.width
+}
+
+// hashes строит два хеша ключа по FNV от его типа и строкового представления,
+// поэтому равные ключи одного типа всегда попадают в одни и те же счётчики
+func hashes(key interface{}) (uint64, uint64) {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%T:%v", key, key)
+	sum := h.Sum64()
+	return sum, (sum >> 32) | 1
+}
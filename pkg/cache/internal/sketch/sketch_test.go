@@ -0,0 +1,60 @@
+package sketch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCountMinSketch_Accuracy проверяет, что оценки не меньше настоящих частот и на малом наборе ключей точны
+func TestCountMinSketch_Accuracy(t *testing.T) {
+	s := New(256, 4)
+	counts := map[interface{}]int{"a": 1, "b": 5, "c": 17, 42: 3, 3.5: 8}
+	for key, n := range counts {
+		for i := 0; i < n; i++ {
+			s.Increment(key)
+		}
+	}
+
+	for key, n := range counts {
+		estimate := s.Estimate(key)
+		assert.GreaterOrEqual(t, estimate, uint64(n), "Estimate must never undercount %v", key)
+		assert.LessOrEqual(t, estimate, uint64(n+2), "Estimate for %v should be close with few keys", key)
+	}
+	assert.LessOrEqual(t, s.Estimate("absent"), uint64(2))
+}
+
+// TestCountMinSketch_KeyTypes проверяет, что ключи разных типов с одинаковым представлением не смешиваются
+func TestCountMinSketch_KeyTypes(t *testing.T) {
+	s := New(1024, 4)
+	for i := 0; i < 10; i++ {
+		s.Increment(1)
+	}
+	assert.Equal(t, uint64(10), s.Estimate(1))
+	assert.Equal(t, uint64(0), s.Estimate("1"))
+}
+
+// TestCountMinSketch_HalveReset проверяет старение и сброс счётчиков
+func TestCountMinSketch_HalveReset(t *testing.T) {
+	s := New(64, 4)
+	for i := 0; i < 20; i++ {
+		s.Increment("a")
+	}
+	for i := 0; i < 7; i++ {
+		s.Increment("b")
+	}
+
+	s.Halve()
+	assert.Equal(t, uint64(10), s.Estimate("a"))
+	assert.Equal(t, uint64(3), s.Estimate("b"))
+
+	s.Reset()
+	assert.Equal(t, uint64(0), s.Estimate("a"))
+	assert.Equal(t, uint64(0), s.Estimate("b"))
+}
+
+// TestNew_InvalidSize проверяет размеры sketch
+func TestNew_InvalidSize(t *testing.T) {
+	assert.Panics(t, func() { New(0, 4) })
+	assert.Panics(t, func() { New(16, 0) })
+}
@@ -0,0 +1,156 @@
+package lfu
+
+import (
+	"LRU_cache/pkg/cache"
+	"math/rand"
+)
+
+// approxSeed - начальное значение генератора выборки, чтобы вытеснение было воспроизводимым
+const approxSeed = 1
+
+// approxItem - элемент приближённого LFU кэша
+type approxItem struct {
+	key       interface{}
+	value     interface{}
+	frequency int
+	touched   uint64 // значение логических часов при последнем обращении
+}
+
+// ApproxLFUCache - приближённый LFU кэш в духе Redis: частоты хранятся в самих элементах без
+// упорядоченных списков, а при вытеснении просматривается случайная выборка из sampleSize элементов
+// и удаляется наименее часто используемый из неё (при равной частоте - дольше не использовавшийся).
+// Вытеснение стоит O(sampleSize) независимо от числа различных частот, обращение - O(1),
+// но жертвой не всегда оказывается глобально наименее частый элемент
+type ApproxLFUCache struct {
+	capacity   int
+	sampleSize int
+	items      []approxItem
+	index      map[interface{}]int // ключ -> позиция в items
+	clock      uint64
+	rng        *rand.Rand
+}
+
+var _ cache.Cache = (*ApproxLFUCache)(nil)
+
+// NewLFUCacheApprox создает приближённый LFU кэш на capacity элементов, просматривающий при вытеснении
+// sampleSize случайных элементов. Если sampleSize не меньше размера кэша, просматриваются все
+func NewLFUCacheApprox(capacity, sampleSize int) *ApproxLFUCache {
+	if capacity <= 0 {
+		panic("capacity must be positive")
+	}
+	if sampleSize <= 0 {
+		panic("sample size must be positive")
+	}
+	return &ApproxLFUCache{
+		capacity:   capacity,
+		sampleSize: sampleSize,
+		items:      make([]approxItem, 0, capacity),
+		index:      make(map[interface{}]int, capacity),
+		rng:        rand.New(rand.NewSource(approxSeed)),
+	}
+}
+
+// Get получает значение по ключу и повышает его частоту
+func (c *ApproxLFUCache) Get(key interface{}) (interface{}, bool) {
+	i, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+	c.touch(i)
+	return c.items[i].value, true
+}
+
+// Put добавляет или обновляет значение. Для существующего ключа частота повышается,
+// новый элемент получает частоту 1, при переполнении вытесняется жертва из выборки
+func (c *ApproxLFUCache) Put(key, value interface{}) {
+	if i, ok := c.index[key]; ok {
+		c.items[i].value = value
+		c.touch(i)
+		return
+	}
+
+	if len(c.items) >= c.capacity {
+		c.removeAt(c.victim())
+	}
+	c.clock++
+	c.index[key] = len(c.items)
+	c.items = append(c.items, approxItem{key: key, value: value, frequency: 1, touched: c.clock})
+}
+
+// Add добавляет значение по правилам интерфейса cache.Cache: возвращает true для нового ключа,
+// для существующего обновляет значение, повышает частоту и возвращает false
+func (c *ApproxLFUCache) Add(key, value interface{}) bool {
+	_, exists := c.index[key]
+	c.Put(key, value)
+	return !exists
+}
+
+// Remove удаляет элемент по ключу, возвращает false, если ключа нет в кэше
+func (c *ApproxLFUCache) Remove(key interface{}) bool {
+	i, ok := c.index[key]
+	if !ok {
+		return false
+	}
+	c.removeAt(i)
+	return true
+}
+
+// Len возвращает текущее количество элементов
+func (c *ApproxLFUCache) Len() int {
+	return len(c.items)
+}
+
+// Clear удаляет все элементы; состояние генератора выборки сохраняется
+func (c *ApproxLFUCache) Clear() {
+	c.items = c.items[:0]
+	c.index = make(map[interface{}]int, c.capacity)
+}
+
+// touch засчитывает обращение к элементу на позиции i
+func (c *ApproxLFUCache) touch(i int) {
+	c.clock++
+	c.items[i].frequency++
+	c.items[i].touched = c.clock
+}
+
+// victim выбирает позицию наименее часто используемого элемента среди выборки
+func (c *ApproxLFUCache) victim() int {
+	if c.sampleSize >= len(c.items) {
+		best := 0
+		for i := 1; i < len(c.items); i++ {
+			if c.less(i, best) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	best := c.rng.Intn(len(c.items))
+	for n := 1; n < c.sampleSize; n++ {
+		if i := c.rng.Intn(len(c.items)); c.less(i, best) {
+			best = i
+		}
+	}
+	return best
+}
+
+// less сообщает, что элемент i вытесняется раньше элемента j
+func (c *ApproxLFUCache) less(i, j int) bool {
+	a, b := c.items[i], c.items[j]
+	if a.frequency != b.frequency {
+		return a.frequency < b.frequency
+	}
+	return a.touched < b.touched
+}
+
+// removeAt удаляет элемент, переставляя на его место последний элемент среза
+func (c *ApproxLFUCache) removeAt(i int) {
+	last := len(c.items) - 1
+	delete(c.index, c.items[i].key)
+	if i != last {
+		c.items[i] = c.items[last]
+		c.index[c.items[i].key] = i
+	}
+	c.items[last] = approxItem{}
+	c.items = c.items[:last]
+}
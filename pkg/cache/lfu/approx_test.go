@@ -0,0 +1,67 @@
+package lfu
+
+import (
+	"LRU_cache/pkg/cache/benchutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApprox_Basic проверяет базовые операции приближённого LFU
+func TestApprox_Basic(t *testing.T) {
+	c := NewLFUCacheApprox(2, 5)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+
+	c.Put("b", 2)
+	c.Put("c", 3)
+	assert.Equal(t, 2, c.Len())
+	_, ok = c.Get("b")
+	assert.False(t, ok, "b has the lowest frequency and should be evicted")
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+}
+
+// TestApprox_VictimUsuallyLeastFrequent проверяет, что жертва из выборки почти всегда
+// попадает в четверть наименее часто используемых элементов
+func TestApprox_VictimUsuallyLeastFrequent(t *testing.T) {
+	const n = 100
+	c := NewLFUCacheApprox(n, 10)
+	for i := 0; i < n; i++ {
+		c.Put(i, i)
+		for j := 0; j < i; j++ {
+			c.Get(i)
+		}
+	}
+
+	const trials = 1000
+	inLowestQuarter := 0
+	for i := 0; i < trials; i++ {
+		if c.items[c.victim()].frequency <= n/4 {
+			inLowestQuarter++
+		}
+	}
+	// При выборке из 10 элементов промах мимо нижней четверти случается с вероятностью 0.75^10 ≈ 6%
+	assert.Greater(t, inLowestQuarter, trials*85/100)
+}
+
+// TestApprox_HitRatioCloseToExact сравнивает долю попаданий с точным LFU на трассе Ципфа
+func TestApprox_HitRatioCloseToExact(t *testing.T) {
+	trace := benchutil.Zipf(100000, 10000, 1.1, 42)
+
+	exact := benchutil.Replay(NewLFUCache(100), trace)
+	approx := benchutil.Replay(NewLFUCacheApprox(100, 16), trace)
+	t.Logf("exact LFU hit ratio %.3f, approximate LFU hit ratio %.3f", exact.HitRatio(), approx.HitRatio())
+	assert.InDelta(t, exact.HitRatio(), approx.HitRatio(), 0.03)
+}
@@ -0,0 +1,43 @@
+package lfu
+
+import (
+	"expvar"
+	"fmt"
+	"sync/atomic"
+)
+
+// Metrics - метрики кэша, публикуемые через expvar
+type Metrics struct {
+	Size      int    `json:"size"`
+	Capacity  int    `json:"capacity"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// PublishExpvar регистрирует в expvar переменную name, которая при чтении отдаёт Metrics в виде JSON.
+// Если переменная с таким именем уже опубликована, возвращается ошибка
+func (c *LFUCache) PublishExpvar(name string) error {
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %q is already published", name)
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return c.metrics()
+	}))
+	return nil
+}
+
+// metrics собирает текущие значения метрик
+func (c *LFUCache) metrics() Metrics {
+	c.mu.Lock()
+	size, capacity := len(c.items), c.capacity
+	c.mu.Unlock()
+
+	return Metrics{
+		Size:      size,
+		Capacity:  capacity,
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Evictions: atomic.LoadUint64(&c.evictions),
+	}
+}
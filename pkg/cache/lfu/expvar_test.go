@@ -0,0 +1,26 @@
+package lfu
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест: опубликованная переменная отдаёт актуальные метрики, повторная публикация возвращает ошибку
+func TestPublishExpvar(t *testing.T) {
+	cache := NewLFUCache(2)
+	assert.NoError(t, cache.PublishExpvar("lfu_test_metrics"))
+	assert.Error(t, cache.PublishExpvar("lfu_test_metrics"), "Duplicate name should return an error")
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("b")
+	cache.Put("c", 3) // вытесняет a
+	cache.Get("a")
+
+	var metrics Metrics
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("lfu_test_metrics").String()), &metrics))
+	assert.Equal(t, Metrics{Size: 2, Capacity: 2, Hits: 1, Misses: 1, Evictions: 1}, metrics)
+}
@@ -1,14 +1,42 @@
 package lfu
 
 import (
+	"LRU_cache/pkg/cache"
 	"container/list"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // CacheItem - элемент кэша
 type CacheItem struct {
 	key       interface{}
 	value     interface{}
-	frequency int // частота использования
+	frequency int   // частота использования
+	cost      int64 // стоимость элемента при ограничении по суммарной стоимости
+
+	windowStart time.Time // начало текущего интервала ограничения частоты
+	windowHits  int       // число увеличений частоты в текущем интервале
+
+	accesses []accessBucket // число обращений по интервалам окна в оконном режиме, не больше windowBuckets+1 интервалов
+
+	lastAccess time.Time // время последнего Get или Put этого ключа
+	seq        uint64    // порядковый номер добавления в кэш, задаёт порядок при TieBreakFIFO
+}
+
+// windowBuckets - число интервалов, на которые делится окно в оконном режиме.
+// Обращения учитываются с точностью до интервала, зато память на элемент ограничена
+const windowBuckets = 8
+
+// accessBucket - число обращений к элементу за один интервал окна
+type accessBucket struct {
+	index int64 // номер интервала, см. bucketIndex
+	count int
 }
 
 // FrequencyNode - узел частоты, содержащий элементы с одной частотой
@@ -17,8 +45,11 @@ type FrequencyNode struct {
 	elements *list.List // двусвязный список элементов с этой частотой
 }
 
-// LFUCache - основной кэш
+// LFUCache - основной кэш. Безопасен для конкурентного использования: операции выполняются
+// под sync.Mutex, колбэки WithOnHit и WithOnMiss вызываются после снятия блокировки
 type LFUCache struct {
+	mu sync.Mutex
+
 	capacity int
 	minFreq  int // минимальная текущая частота
 
@@ -26,50 +57,440 @@ type LFUCache struct {
 	items     map[interface{}]*list.Element // key -> элемент в elements списке
 	freqLists map[int]*list.Element         // freq -> FrequencyNode в freqNodes
 	freqNodes *list.List                    // список FrequencyNode, отсортированный по частоте
+
+	now func() time.Time // источник текущего времени
+
+	// Ограничение роста частоты: не более rateLimitMax увеличений за rateLimitInterval
+	rateLimitMax      int
+	rateLimitInterval time.Duration
+
+	// Оконный режим: учитываются только обращения за последние accessWindow
+	accessWindow  time.Duration
+	expiredBucket int64 // интервал, для которого частоты уже пересчитаны (0 - ещё не пересчитывались)
+
+	// Ограничение по суммарной стоимости вместо количества элементов (0 - не используется)
+	maxCost   int64
+	totalCost int64
+
+	// Счётчики статистики, изменяются атомарно
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	events chan cache.EvictedEntry // канал событий об удалении, nil - никто не подписан
+	closed bool                    // кэш закрыт через Close, новые подписки на события не создаются
+
+	tieBreak TieBreak // порядок вытеснения элементов с одинаковой частотой
+	nextSeq  uint64   // порядковый номер следующего добавленного элемента
+
+	frozen bool // режим только для чтения, см. Freeze
+
+	maxFreq int // предельная частота элемента (0 - без ограничения), см. WithMaxFrequency
+
+	keyFunc func(key interface{}) string // строит строковый ключ внутренних map, см. WithKeyFunc
+
+	onHit  func(key interface{}) // вызывается на попадание Get, см. WithOnHit
+	onMiss func(key interface{}) // вызывается на промах Get, см. WithOnMiss
+}
+
+// TieBreak - правило выбора жертвы среди элементов с одинаковой частотой
+type TieBreak int
+
+const (
+	// TieBreakLRU вытесняет элемент, дольше всех не получавший обращений (по умолчанию)
+	TieBreakLRU TieBreak = iota
+	// TieBreakFIFO вытесняет элемент, раньше всех добавленный в кэш, независимо от обращений
+	TieBreakFIFO
+)
+
+// Stats - снимок статистики кэша
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// LFUCache реализует общий интерфейс кэша и взаимозаменяем с LRU
+var _ cache.Cache = (*LFUCache)(nil)
+
+// Option настраивает LFU кэш при создании
+type Option func(*LFUCache)
+
+// WithClock подменяет источник текущего времени (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(c *LFUCache) {
+		c.now = now
+	}
+}
+
+// WithKeyFunc задаёт функцию, строящую по ключу стабильную строку, на которой строятся внутренние map.
+// Так в кэше можно хранить несравнимые ключи, например []byte: равные по keyFunc ключи считаются одним.
+// Исходный ключ сохраняется в CacheItem и возвращается из Keys и событий. ToMap и NewLFUFromMap
+// по-прежнему требуют сравнимых ключей
+func WithKeyFunc(keyFunc func(key interface{}) string) Option {
+	return func(c *LFUCache) {
+		c.keyFunc = keyFunc
+	}
+}
+
+// mapKey возвращает ключ внутренних map: результат keyFunc, если она задана, иначе сам ключ
+func (c *LFUCache) mapKey(key interface{}) interface{} {
+	if c.keyFunc == nil {
+		return key
+	}
+	return c.keyFunc(key)
+}
+
+// WithOnHit задаёт хук, вызываемый из Get при попадании, например для трассировки обращений.
+// Хук вызывается после повышения частоты, кэш не синхронизирован, поэтому блокировок при вызове нет
+func WithOnHit(onHit func(key interface{})) Option {
+	return func(c *LFUCache) {
+		c.onHit = onHit
+	}
+}
+
+// WithOnMiss задаёт хук, вызываемый из Get при промахе
+func WithOnMiss(onMiss func(key interface{})) Option {
+	return func(c *LFUCache) {
+		c.onMiss = onMiss
+	}
+}
+
+// WithTieBreak задаёт правило выбора жертвы среди элементов с одинаковой частотой.
+// При TieBreakFIFO повышение частоты ставит элемент в список новой частоты по порядку добавления в кэш,
+// а не в конец, что стоит O(размер списка)
+func WithTieBreak(tieBreak TieBreak) Option {
+	return func(c *LFUCache) {
+		c.tieBreak = tieBreak
+	}
+}
+
+// WithMaxFrequency ограничивает частоту элемента значением maxFreq, а значит, и число узлов частот.
+// Обращения к элементу с предельной частотой переносят его в конец списка этой частоты, как при LRU
+func WithMaxFrequency(maxFreq int) Option {
+	if maxFreq <= 0 {
+		panic("max frequency must be positive")
+	}
+	return func(c *LFUCache) {
+		c.maxFreq = maxFreq
+	}
+}
+
+// WithFrequencyRateLimit ограничивает рост частоты элемента: не более maxPerInterval увеличений за interval.
+// Лишние обращения возвращают значение, но частоту не повышают
+func WithFrequencyRateLimit(maxPerInterval int, interval time.Duration) Option {
+	return func(c *LFUCache) {
+		c.rateLimitMax = maxPerInterval
+		c.rateLimitInterval = interval
+	}
+}
+
+// NewLFUCacheWindowed создает LFU кэш, в котором при вытеснении учитываются только обращения
+// за последний window. Частота элемента без свежих обращений опускается до 1.
+// Обращения группируются по интервалам длиной window/8, поэтому граница окна соблюдается с этой точностью
+func NewLFUCacheWindowed(capacity int, window time.Duration, opts ...Option) *LFUCache {
+	if window <= 0 {
+		panic("window must be positive")
+	}
+	c := NewLFUCache(capacity, opts...)
+	c.accessWindow = window
+	return c
+}
+
+// NewLFUCacheWithMaxCost создает LFU кэш, ограниченный суммарной стоимостью элементов, а не их количеством.
+// Элементы, добавленные через Put, имеют стоимость 1
+func NewLFUCacheWithMaxCost(maxCost int64, opts ...Option) *LFUCache {
+	if maxCost <= 0 {
+		panic("max cost must be positive")
+	}
+	return newLFUCache(math.MaxInt, maxCost, opts...)
 }
 
-// NewLFUCache создает новый LFU кэш
-func NewLFUCache(capacity int) *LFUCache {
+// NewLFUCache создает новый LFU кэш. Результат можно присвоить переменной типа cache.Cache.
+// При capacity <= 0 вызывается panic; кэш нулевой ёмкости создаёт NewLFUCacheAllowZero
+func NewLFUCache(capacity int, opts ...Option) *LFUCache {
 	if capacity <= 0 {
 		panic("capacity must be positive")
 	}
-	return &LFUCache{
+	return newLFUCache(capacity, 0, opts...)
+}
+
+// NewLFUCacheE работает как NewLFUCache, но вместо panic возвращает ошибку для capacity <= 0
+func NewLFUCacheE(capacity int, opts ...Option) (*LFUCache, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("lfu: capacity must be positive, got %d", capacity)
+	}
+	return newLFUCache(capacity, 0, opts...), nil
+}
+
+// NewLFUCacheAllowZero работает как NewLFUCache, но допускает ёмкость 0, как LRU:
+// такой кэш принимает Put, ничего не хранит и всегда промахивается на Get
+func NewLFUCacheAllowZero(capacity int, opts ...Option) *LFUCache {
+	if capacity < 0 {
+		panic("capacity must not be negative")
+	}
+	return newLFUCache(capacity, 0, opts...)
+}
+
+func newLFUCache(capacity int, maxCost int64, opts ...Option) *LFUCache {
+	c := &LFUCache{
 		capacity:  capacity,
+		maxCost:   maxCost,
 		minFreq:   0,
-		items:     make(map[interface{}]*list.Element),
-		freqLists: make(map[int]*list.Element),
 		freqNodes: list.New(),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.items = make(map[interface{}]*list.Element, c.sizeHint())
+	// Число различных частот заранее известно, только если задан maxFreq
+	c.freqLists = make(map[int]*list.Element, c.maxFreq)
+	return c
+}
+
+// sizeHint возвращает размер, под который заранее выделяются map элементов, чтобы избежать
+// перехеширования при заполнении. Кэш, ограниченный стоимостью, число элементов заранее не знает
+func (c *LFUCache) sizeHint() int {
+	if c.maxCost > 0 {
+		return 0
+	}
+	return c.capacity
+}
+
+// NewLFUFromMap создает LFU кэш и загружает в него элементы m с частотой 1.
+// Порядок обхода map не определён, поэтому при len(m) > capacity неизвестно, какие элементы будут вытеснены
+func NewLFUFromMap(capacity int, m map[interface{}]interface{}, opts ...Option) *LFUCache {
+	c := NewLFUCache(capacity, opts...)
+	c.PutAll(m)
+	return c
 }
 
 // Get получает значение по ключу
 func (c *LFUCache) Get(key interface{}) (interface{}, bool) {
-	if elem, ok := c.items[key]; ok {
-		// Обновляем частоту использования
+	c.mu.Lock()
+	value, ok := c.get(key)
+	c.mu.Unlock()
+
+	c.notifyAccess(key, ok)
+	return value, ok
+}
+
+// get ищет значение, учитывает попадание или промах и повышает частоту найденного элемента
+func (c *LFUCache) get(key interface{}) (interface{}, bool) {
+	elem, ok := c.items[c.mapKey(key)]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&c.hits, 1)
+	if !c.frozen {
+		c.incrementFrequency(elem)
+	}
+	return elem.Value.(*CacheItem).value, true
+}
+
+// notifyAccess вызывает колбэк попадания или промаха. Вызывается после снятия блокировки
+func (c *LFUCache) notifyAccess(key interface{}, hit bool) {
+	if hit && c.onHit != nil {
+		c.onHit(key)
+	}
+	if !hit && c.onMiss != nil {
+		c.onMiss(key)
+	}
+}
+
+// Touch повышает частоту элемента, не читая значение, и сообщает, есть ли ключ в кэше.
+// В отличие от Get, не учитывается в статистике
+func (c *LFUCache) Touch(key interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[c.mapKey(key)]
+	if !ok {
+		return false
+	}
+	if !c.frozen {
 		c.incrementFrequency(elem)
-		item := elem.Value.(*CacheItem)
-		return item.value, true
 	}
-	return nil, false
+	return true
 }
 
 // Put добавляет или обновляет значение
 func (c *LFUCache) Put(key, value interface{}) {
-	if c.capacity == 0 {
-		return
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(key, value)
+}
+
+// put добавляет или обновляет значение со стоимостью 1 и возвращает первый вытесненный элемент (nil, если вытеснения не было)
+func (c *LFUCache) put(key, value interface{}) *CacheItem {
+	if c.frozen {
+		return nil
 	}
 
 	// Если ключ уже существует, обновляем значение и частоту
-	if elem, ok := c.items[key]; ok {
+	if elem, ok := c.items[c.mapKey(key)]; ok {
 		item := elem.Value.(*CacheItem)
 		item.value = value
 		c.incrementFrequency(elem)
-		return
+		return nil
 	}
 
-	// Если достигли capacity, удаляем LFU элемент
-	if len(c.items) >= c.capacity {
-		c.evict()
+	return c.insert(key, value, 1)
+}
+
+// Replace обновляет значение существующего ключа и повышает его частоту, как Put.
+// Отсутствующий ключ не добавляется, в этом случае возвращается false
+func (c *LFUCache) Replace(key, value interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[c.mapKey(key)]; !ok || c.frozen {
+		return false
+	}
+	c.put(key, value)
+	return true
+}
+
+// PutEvict работает как Put и возвращает элемент, вытесненный, чтобы освободить место.
+// При ограничении по суммарной стоимости может вытесняться несколько элементов, тогда возвращается первый из них
+func (c *LFUCache) PutEvict(key, value interface{}) (evictedKey, evictedValue interface{}, evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := c.put(key, value)
+	if item == nil {
+		return nil, nil, false
+	}
+	return item.key, item.value, true
+}
+
+// PutIfAbsent добавляет value, только если ключа нет в кэше, и возвращает его с loaded=false.
+// Для существующего ключа возвращает текущее значение и loaded=true, не меняя ни значение, ни частоту
+func (c *LFUCache) PutIfAbsent(key, value interface{}) (actual interface{}, loaded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[c.mapKey(key)]; ok {
+		return elem.Value.(*CacheItem).value, true
+	}
+	c.put(key, value)
+	return value, false
+}
+
+// PutAll добавляет или обновляет все элементы пачкой под одной блокировкой, как последовательные вызовы Put.
+// Порядок вставки внутри пачки не определён, как и порядок обхода map
+func (c *LFUCache) PutAll(items map[interface{}]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, value := range items {
+		c.put(key, value)
+	}
+}
+
+// Merge добавляет в кэш все элементы other в его порядке вытеснения, так что самые приоритетные в other
+// добавляются последними и вытесняются позже остальных. Если ключ уже есть в кэше, сохраняется
+// результат resolve(key, текущее значение, значение из other), а частота ключа растёт как при Put.
+// Снимок other берётся до блокировки, все элементы добавляются под одной блокировкой,
+// поэтому resolve вызывается под блокировкой и не должен обращаться к кэшу
+func (c *LFUCache) Merge(other cache.EntryLister, resolve func(key, a, b interface{}) interface{}) {
+	entries := other.Entries()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range entries {
+		value := entry.Value
+		if elem, exists := c.items[c.mapKey(entry.Key)]; exists {
+			value = resolve(entry.Key, elem.Value.(*CacheItem).value, entry.Value)
+		}
+		c.put(entry.Key, value)
+	}
+}
+
+// GetAll возвращает найденные значения для keys под одной блокировкой, повышая их частоту
+// и учитывая статистику как Get. Отсутствующие ключи в результат не попадают.
+// Колбэки попаданий и промахов вызываются после снятия блокировки
+func (c *LFUCache) GetAll(keys []interface{}) map[interface{}]interface{} {
+	c.mu.Lock()
+	values, _, found := c.getMultiple(keys)
+	c.mu.Unlock()
+
+	for i, key := range keys {
+		c.notifyAccess(key, found[i])
+	}
+	return values
+}
+
+// getMultiple ищет значения для keys, возвращая найденные, ненайденные в порядке запроса
+// и признак попадания для каждого ключа
+func (c *LFUCache) getMultiple(keys []interface{}) (values map[interface{}]interface{}, misses []interface{}, found []bool) {
+	values = make(map[interface{}]interface{}, len(keys))
+	found = make([]bool, len(keys))
+	for i, key := range keys {
+		if value, ok := c.get(key); ok {
+			values[key] = value
+			found[i] = true
+		} else {
+			misses = append(misses, key)
+		}
+	}
+	return values, misses, found
+}
+
+// GetMultiple работает как GetAll, в том числе под одной блокировкой, и дополнительно возвращает
+// ненайденные ключи в порядке запроса, например, чтобы догрузить их из хранилища одним запросом
+func (c *LFUCache) GetMultiple(keys []interface{}) (values map[interface{}]interface{}, misses []interface{}) {
+	c.mu.Lock()
+	values, misses, found := c.getMultiple(keys)
+	c.mu.Unlock()
+
+	for i, key := range keys {
+		c.notifyAccess(key, found[i])
+	}
+	return values, misses
+}
+
+// PutWithCost работает как Put, но учитывает стоимость элемента при ограничении по суммарной стоимости.
+// Возвращает true, если запись принята - как для нового ключа, так и для обновления существующего.
+// Элемент дороже maxCost или с отрицательной стоимостью отклоняется целиком: возвращается false, кэш не меняется.
+// Для освобождения места вытесняется столько наименее часто используемых элементов, сколько нужно
+func (c *LFUCache) PutWithCost(key, value interface{}, cost int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.frozen || cost < 0 || c.maxCost > 0 && cost > c.maxCost {
+		return false
+	}
+
+	if elem, ok := c.items[c.mapKey(key)]; ok {
+		item := elem.Value.(*CacheItem)
+		item.value = value
+		c.totalCost += cost - item.cost
+		item.cost = cost
+		c.incrementFrequency(c.items[c.mapKey(key)])
+		c.evictOverCost(item)
+		return true
+	}
+
+	c.insert(key, value, cost)
+	return true
+}
+
+// insert добавляет новый элемент с частотой 1, предварительно освобождая место, и возвращает
+// первый вытесненный элемент (nil, если вытеснения не было). При нулевой ёмкости ничего не делает
+func (c *LFUCache) insert(key, value interface{}, cost int64) (evicted *CacheItem) {
+	if c.capacity == 0 {
+		return nil
+	}
+	if c.maxCost > 0 {
+		if c.totalCost+cost > c.maxCost {
+			c.expireAccesses()
+			c.totalCost += cost
+			evicted = c.evictOverCost(nil)
+			c.totalCost -= cost
+		}
+	} else if len(c.items) >= c.capacity {
+		// Если достигли capacity, удаляем LFU элемент
+		c.expireAccesses()
+		evicted = c.evict(cache.EvictedCapacity)
 	}
 
 	// Создаем новый элемент с частотой 1
@@ -77,40 +498,465 @@ func (c *LFUCache) Put(key, value interface{}) {
 		key:       key,
 		value:     value,
 		frequency: 1,
+		cost:      cost,
 	}
+	item.lastAccess = c.now()
+	item.seq = c.nextSeq
+	c.nextSeq++
+	c.recordAccess(item)
 
-	// Добавляем в список частоты 1
-	elem := c.addToFrequencyList(1, item)
-	c.items[key] = elem
+	// Добавляем в список частоты 1: его узел может быть только первым
+	elem := c.addToFrequencyListAfter(nil, 1, item)
+	c.items[c.mapKey(key)] = elem
+	c.totalCost += cost
 
 	// Обновляем minFreq
 	c.minFreq = 1
+	return evicted
+}
+
+// evictOverCost вытесняет наименее часто используемые элементы, кроме keep, пока суммарная стоимость превышает maxCost,
+// и возвращает первый вытесненный элемент
+func (c *LFUCache) evictOverCost(keep *CacheItem) (first *CacheItem) {
+	if c.maxCost <= 0 {
+		return nil
+	}
+	for c.totalCost > c.maxCost {
+		victim := c.evictionCandidate(keep)
+		if victim == nil {
+			break
+		}
+		item := victim.Value.(*CacheItem)
+		c.removeFromFrequencyList(item.frequency, victim)
+		delete(c.items, c.mapKey(item.key))
+		c.totalCost -= item.cost
+		atomic.AddUint64(&c.evictions, 1)
+		c.emit(item, cache.EvictedCapacity)
+		if first == nil {
+			first = item
+		}
+	}
+	if first != nil {
+		c.recomputeMinFreq()
+	}
+	return first
+}
+
+// evictionCandidate возвращает первый в порядке вытеснения элемент, отличный от skip
+func (c *LFUCache) evictionCandidate(skip *CacheItem) *list.Element {
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			if e.Value.(*CacheItem) != skip {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// Add добавляет значение по правилам интерфейса cache.Cache: возвращает true для нового ключа,
+// для существующего обновляет значение, повышает частоту и возвращает false
+func (c *LFUCache) Add(key, value interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, exists := c.items[c.mapKey(key)]
+	c.put(key, value)
+	return !exists && !c.frozen
+}
+
+// Remove удаляет элемент по ключу, возвращает false, если ключа нет в кэше
+func (c *LFUCache) Remove(key interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.remove(key)
+}
+
+// remove удаляет элемент по ключу и отправляет событие удаления
+func (c *LFUCache) remove(key interface{}) bool {
+	elem, ok := c.items[c.mapKey(key)]
+	if !ok || c.frozen {
+		return false
+	}
+
+	item := elem.Value.(*CacheItem)
+	c.removeFromFrequencyList(item.frequency, elem)
+	delete(c.items, c.mapKey(key))
+	c.totalCost -= item.cost
+	c.emit(item, cache.EvictedManual)
+
+	// Если удалили последний элемент с минимальной частотой, берём следующую частоту
+	if item.frequency == c.minFreq && c.getFrequencyList(item.frequency) == nil {
+		c.recomputeMinFreq()
+	}
+	return true
+}
+
+// RemovePrefix удаляет все элементы со строковыми ключами, начинающимися с prefix, и возвращает их количество.
+// Ключи других типов не затрагиваются
+func (c *LFUCache) RemovePrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var keys []interface{}
+	for _, elem := range c.items {
+		key := elem.Value.(*CacheItem).key
+		if s, ok := key.(string); ok && strings.HasPrefix(s, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	removed := 0
+	for _, key := range keys {
+		if c.remove(key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Resize меняет ёмкость кэша и при уменьшении вытесняет наименее часто используемые элементы.
+// Возвращает количество вытесненных элементов. Кэш, ограниченный стоимостью, по количеству не вытесняет
+func (c *LFUCache) Resize(newCapacity int) int {
+	if newCapacity <= 0 {
+		panic("capacity must be positive")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxCost > 0 {
+		return 0
+	}
+	c.capacity = newCapacity
+	if len(c.items) > c.capacity {
+		c.expireAccesses()
+	}
+
+	evicted := 0
+	for len(c.items) > c.capacity {
+		c.evict(cache.EvictedCapacity)
+		evicted++
+	}
+	if evicted > 0 {
+		c.recomputeMinFreq()
+	}
+	return evicted
+}
+
+// EvictN вытесняет до n наименее часто используемых элементов и возвращает, сколько удалось удалить
+func (c *LFUCache) EvictN(n int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n <= 0 || len(c.items) == 0 || c.frozen {
+		return 0
+	}
+	c.expireAccesses()
+
+	evicted := 0
+	for evicted < n && len(c.items) > 0 {
+		c.evict(cache.EvictedManual)
+		evicted++
+	}
+	c.recomputeMinFreq()
+	return evicted
+}
+
+// Clone возвращает независимую копию кэша с теми же элементами, частотами, порядком вытеснения,
+// настройками и статистикой. Значения копируются поверхностно: указатели и ссылочные типы
+// в копии и оригинале общие. Подписка на события удаления не копируется
+func (c *LFUCache) Clone() *LFUCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clone := &LFUCache{
+		capacity:          c.capacity,
+		minFreq:           c.minFreq,
+		items:             make(map[interface{}]*list.Element, len(c.items)),
+		freqLists:         make(map[int]*list.Element, len(c.freqLists)),
+		freqNodes:         list.New(),
+		now:               c.now,
+		rateLimitMax:      c.rateLimitMax,
+		rateLimitInterval: c.rateLimitInterval,
+		accessWindow:      c.accessWindow,
+		expiredBucket:     c.expiredBucket,
+		maxCost:           c.maxCost,
+		totalCost:         c.totalCost,
+		hits:              atomic.LoadUint64(&c.hits),
+		misses:            atomic.LoadUint64(&c.misses),
+		evictions:         atomic.LoadUint64(&c.evictions),
+		tieBreak:          c.tieBreak,
+		nextSeq:           c.nextSeq,
+		frozen:            c.frozen,
+		maxFreq:           c.maxFreq,
+		keyFunc:           c.keyFunc,
+		onHit:             c.onHit,
+		onMiss:            c.onMiss,
+	}
+
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		freqNode := node.Value.(*FrequencyNode)
+		nodeCopy := &FrequencyNode{freq: freqNode.freq, elements: list.New()}
+		clone.freqLists[freqNode.freq] = clone.freqNodes.PushBack(nodeCopy)
+		for e := freqNode.elements.Front(); e != nil; e = e.Next() {
+			item := *e.Value.(*CacheItem)
+			item.accesses = append([]accessBucket(nil), item.accesses...)
+			clone.items[c.mapKey(item.key)] = nodeCopy.elements.PushBack(&item)
+		}
+	}
+	return clone
+}
+
+// Freeze переводит кэш в режим только для чтения: Put, PutWithCost, Add, Remove и EvictN ничего не меняют,
+// а Get возвращает значения без повышения частоты. Clear, Resize, Decay и Load остаются доступными
+// как явные административные операции
+func (c *LFUCache) Freeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frozen = true
+}
+
+// Unfreeze возвращает кэш в обычный режим
+func (c *LFUCache) Unfreeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frozen = false
+}
+
+// Frozen сообщает, находится ли кэш в режиме только для чтения
+func (c *LFUCache) Frozen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.frozen
+}
+
+// Decay умножает частоты всех элементов на factor (0 < factor <= 1), не опуская их ниже 1,
+// и перестраивает списки частот. Относительный порядок вытеснения сохраняется
+func (c *LFUCache) Decay(factor float64) {
+	if factor <= 0 || factor > 1 {
+		panic("decay factor must be in (0, 1]")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebuildFrequencies(func(item *CacheItem) int {
+		return int(float64(item.frequency) * factor)
+	})
+}
+
+// rebuildFrequencies назначает элементам новые частоты (не ниже 1) и перестраивает списки частот.
+// Элементы переносятся в порядке вытеснения, поэтому при равных новых частотах их порядок сохраняется
+func (c *LFUCache) rebuildFrequencies(newFrequency func(item *CacheItem) int) {
+	items := make([]*CacheItem, 0, len(c.items))
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			items = append(items, e.Value.(*CacheItem))
+		}
+	}
+
+	c.freqLists = make(map[int]*list.Element, c.maxFreq)
+	c.freqNodes.Init()
+	for _, item := range items {
+		newFreq := c.capFrequency(newFrequency(item))
+		if newFreq < 1 {
+			newFreq = 1
+		}
+		item.frequency = newFreq
+		c.items[c.mapKey(item.key)] = c.addToFrequencyList(newFreq, item)
+	}
+	c.recomputeMinFreq()
+}
+
+// recordAccess учитывает обращение к элементу в оконном режиме и отбрасывает интервалы старше окна
+func (c *LFUCache) recordAccess(item *CacheItem) {
+	if c.accessWindow <= 0 {
+		return
+	}
+	current := c.bucketIndex(c.now())
+	if n := len(item.accesses); n > 0 && item.accesses[n-1].index == current {
+		item.accesses[n-1].count++
+	} else {
+		item.accesses = append(item.accesses, accessBucket{index: current, count: 1})
+	}
+	trimAccesses(item, current)
+}
+
+// bucketIndex возвращает номер интервала окна, в который попадает момент t
+func (c *LFUCache) bucketIndex(t time.Time) int64 {
+	width := int64(c.accessWindow / windowBuckets)
+	if width == 0 {
+		width = 1
+	}
+	return t.UnixNano() / width
+}
+
+// trimAccesses отбрасывает интервалы, вышедшие из окна, и возвращает число обращений в оставшихся
+func trimAccesses(item *CacheItem, current int64) int {
+	stale := 0
+	for stale < len(item.accesses) && item.accesses[stale].index <= current-windowBuckets {
+		stale++
+	}
+	item.accesses = item.accesses[stale:]
+
+	fresh := 0
+	for _, bucket := range item.accesses {
+		fresh += bucket.count
+	}
+	return fresh
+}
+
+// expireAccesses в оконном режиме пересчитывает частоты по свежим обращениям. Пересчёт выполняется
+// не чаще раза за интервал окна, а в списках частот переставляются только элементы, частота которых изменилась
+func (c *LFUCache) expireAccesses() {
+	if c.accessWindow <= 0 {
+		return
+	}
+	current := c.bucketIndex(c.now())
+	if current == c.expiredBucket {
+		return
+	}
+	c.expiredBucket = current
+
+	type change struct {
+		elem    *list.Element
+		newFreq int
+	}
+	var changes []change
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			item := e.Value.(*CacheItem)
+			newFreq := c.capFrequency(trimAccesses(item, current))
+			if newFreq < 1 {
+				newFreq = 1
+			}
+			if newFreq != item.frequency {
+				changes = append(changes, change{elem: e, newFreq: newFreq})
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	// Сначала убираем все изменившиеся элементы, затем возвращаем их в порядке вытеснения
+	for _, ch := range changes {
+		c.removeFromFrequencyList(ch.elem.Value.(*CacheItem).frequency, ch.elem)
+	}
+	for _, ch := range changes {
+		item := ch.elem.Value.(*CacheItem)
+		item.frequency = ch.newFreq
+		c.items[c.mapKey(item.key)] = c.addToFrequencyList(ch.newFreq, item)
+	}
+	c.recomputeMinFreq()
+}
+
+// recomputeMinFreq берёт minFreq из первого узла частот (0 для пустого кэша)
+func (c *LFUCache) recomputeMinFreq() {
+	if front := c.freqNodes.Front(); front != nil {
+		c.minFreq = front.Value.(*FrequencyNode).freq
+	} else {
+		c.minFreq = 0
+	}
 }
 
 // incrementFrequency увеличивает частоту элемента
 func (c *LFUCache) incrementFrequency(elem *list.Element) {
 	item := elem.Value.(*CacheItem)
+	item.lastAccess = c.now()
+	if !c.allowIncrement(item) {
+		return
+	}
+	c.recordAccess(item)
 	oldFreq := item.frequency
+	if c.maxFreq > 0 && oldFreq >= c.maxFreq {
+		if c.tieBreak == TieBreakLRU {
+			c.getFrequencyList(oldFreq).MoveToBack(elem)
+		}
+		return
+	}
 	newFreq := oldFreq + 1
 
+	// Если элемент один в своём узле, а узла newFreq ещё нет, достаточно переименовать узел на месте:
+	// порядок узлов сохраняется, элемент и узел не пересоздаются
+	freqNodeElem := c.freqLists[oldFreq]
+	freqNode := freqNodeElem.Value.(*FrequencyNode)
+	if next := freqNodeElem.Next(); freqNode.elements.Len() == 1 &&
+		(next == nil || next.Value.(*FrequencyNode).freq != newFreq) {
+		delete(c.freqLists, oldFreq)
+		freqNode.freq = newFreq
+		c.freqLists[newFreq] = freqNodeElem
+		item.frequency = newFreq
+		c.recomputeMinFreq()
+		return
+	}
+
+	// Узел новой частоты соседствует с текущим, поэтому вставляем относительно него до удаления элемента
+	newElem := c.addToFrequencyListAfter(freqNodeElem, newFreq, item)
+
 	// Удаляем из старого списка частот
 	c.removeFromFrequencyList(oldFreq, elem)
-
-	// Добавляем в новый список частот
-	newElem := c.addToFrequencyList(newFreq, item)
-	c.items[item.key] = newElem
+	c.items[c.mapKey(item.key)] = newElem
 
 	// Обновляем item
 	updatedItem := newElem.Value.(*CacheItem)
 	updatedItem.frequency = newFreq
 
-	// Если старый список частот пуст и это была minFreq, обновляем minFreq
-	if oldFreq == c.minFreq && c.getFrequencyList(oldFreq).Len() == 0 {
-		c.minFreq = newFreq
+	// minFreq берём из первого узла частот: так он верен и после переименования или удаления узла oldFreq,
+	// в том числе когда Put обновляет единственный ключ с минимальной частотой
+	c.recomputeMinFreq()
+}
+
+// capFrequency ограничивает частоту значением maxFreq, если оно задано
+func (c *LFUCache) capFrequency(freq int) int {
+	if c.maxFreq > 0 && freq > c.maxFreq {
+		return c.maxFreq
+	}
+	return freq
+}
+
+// allowIncrement проверяет ограничение роста частоты и учитывает очередное увеличение
+func (c *LFUCache) allowIncrement(item *CacheItem) bool {
+	if c.rateLimitMax <= 0 {
+		return true
+	}
+
+	now := c.now()
+	if now.Sub(item.windowStart) >= c.rateLimitInterval {
+		item.windowStart = now
+		item.windowHits = 0
 	}
+	if item.windowHits >= c.rateLimitMax {
+		return false
+	}
+	item.windowHits++
+	return true
 }
 
-// addToFrequencyList добавляет элемент в список заданной частоты
+// addToFrequencyListAfter добавляет элемент в список частоты freq за O(1), если узел этой частоты
+// следует сразу за prev (или является первым, если prev == nil) либо должен быть создан на этом месте
+func (c *LFUCache) addToFrequencyListAfter(prev *list.Element, freq int, item *CacheItem) *list.Element {
+	next := c.freqNodes.Front()
+	if prev != nil {
+		next = prev.Next()
+	}
+
+	freqNodeElem := next
+	if next == nil || next.Value.(*FrequencyNode).freq != freq {
+		freqNode := &FrequencyNode{
+			freq:     freq,
+			elements: list.New(),
+		}
+		if prev == nil {
+			freqNodeElem = c.freqNodes.PushFront(freqNode)
+		} else {
+			freqNodeElem = c.freqNodes.InsertAfter(freqNode, prev)
+		}
+		c.freqLists[freq] = freqNodeElem
+	}
+	return c.pushItem(freqNodeElem.Value.(*FrequencyNode).elements, item)
+}
+
+// addToFrequencyList добавляет элемент в список заданной частоты.
+// Место узла ищется перебором, поэтому метод используется только при перестройке списков с произвольными частотами
 func (c *LFUCache) addToFrequencyList(freq int, item *CacheItem) *list.Element {
 	// Ищем или создаем FrequencyNode для этой частоты
 	var freqNodeElem *list.Element
@@ -129,7 +975,21 @@ func (c *LFUCache) addToFrequencyList(freq int, item *CacheItem) *list.Element {
 
 	// Добавляем элемент в список этой частоты
 	freqNode := freqNodeElem.Value.(*FrequencyNode)
-	return freqNode.elements.PushBack(item)
+	return c.pushItem(freqNode.elements, item)
+}
+
+// pushItem добавляет элемент в список частоты: в конец при TieBreakLRU,
+// по порядку добавления в кэш при TieBreakFIFO
+func (c *LFUCache) pushItem(elements *list.List, item *CacheItem) *list.Element {
+	if c.tieBreak == TieBreakFIFO {
+		for e := elements.Back(); e != nil; e = e.Prev() {
+			if e.Value.(*CacheItem).seq < item.seq {
+				return elements.InsertAfter(item, e)
+			}
+		}
+		return elements.PushFront(item)
+	}
+	return elements.PushBack(item)
 }
 
 // insertFrequencyNode вставляет FrequencyNode в отсортированный список
@@ -177,10 +1037,175 @@ func (c *LFUCache) getFrequencyList(freq int) *list.List {
 	return nil
 }
 
-// evict удаляет наименее часто используемый элемент
-func (c *LFUCache) evict() {
+// Keys возвращает снимок ключей от наименее к наиболее часто используемым,
+// внутри одной частоты - в порядке LRU (в начале кандидаты на вытеснение)
+func (c *LFUCache) Keys() []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]interface{}, 0, len(c.items))
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			keys = append(keys, e.Value.(*CacheItem).key)
+		}
+	}
+	return keys
+}
+
+// Entries возвращает согласованный снимок элементов, снятый под одной блокировкой, в порядке вытеснения,
+// как Keys. Rank - частота элемента. Частоты не меняются
+func (c *LFUCache) Entries() []cache.Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]cache.Entry, 0, len(c.items))
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			item := e.Value.(*CacheItem)
+			entries = append(entries, cache.Entry{Key: item.key, Value: item.value, Rank: item.frequency})
+		}
+	}
+	return entries
+}
+
+// ToMap возвращает копию всех элементов кэша. Частоты не меняются
+func (c *LFUCache) ToMap() map[interface{}]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := make(map[interface{}]interface{}, len(c.items))
+	for _, elem := range c.items {
+		item := elem.Value.(*CacheItem)
+		m[item.key] = item.value
+	}
+	return m
+}
+
+// Values возвращает снимок значений в том же порядке, что и Keys. Частоты не меняются
+func (c *LFUCache) Values() []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([]interface{}, 0, len(c.items))
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			values = append(values, e.Value.(*CacheItem).value)
+		}
+	}
+	return values
+}
+
+// Range вызывает f для каждого элемента в порядке Keys, пока f возвращает true.
+// Частоты не меняются; обход идёт по снимку, поэтому f может изменять кэш
+func (c *LFUCache) Range(f func(key, value interface{}) bool) {
+	c.mu.Lock()
+	items := make([]CacheItem, 0, len(c.items))
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			items = append(items, *e.Value.(*CacheItem))
+		}
+	}
+	c.mu.Unlock()
+
+	for _, item := range items {
+		if !f(item.key, item.value) {
+			return
+		}
+	}
+}
+
+// Metadata возвращает время последнего обращения к ключу (Get или Put) и его текущую частоту.
+// Сам вызов частоту не меняет
+func (c *LFUCache) Metadata(key interface{}) (lastAccess time.Time, count int, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, exists := c.items[c.mapKey(key)]
+	if !exists {
+		return time.Time{}, 0, false
+	}
+	item := elem.Value.(*CacheItem)
+	return item.lastAccess, item.frequency, true
+}
+
+// MinFrequency возвращает текущую минимальную частоту (0 для пустого кэша)
+func (c *LFUCache) MinFrequency() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.minFreq
+}
+
+// LeastFrequent возвращает элемент, который будет вытеснен следующим, ничего не изменяя.
+// Для пустого кэша ok=false
+func (c *LFUCache) LeastFrequent() (key, value interface{}, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	front := c.freqNodes.Front()
+	if front == nil {
+		return nil, nil, false
+	}
+	item := front.Value.(*FrequencyNode).elements.Front().Value.(*CacheItem)
+	return item.key, item.value, true
+}
+
+// String возвращает содержимое кэша, сгруппированное по возрастанию частоты, в виде {1: [k=v, ...], 2: [...]}.
+// Внутри частоты элементы идут в порядке вытеснения, частоты не меняются
+func (c *LFUCache) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		if node != c.freqNodes.Front() {
+			b.WriteString(", ")
+		}
+		freqNode := node.Value.(*FrequencyNode)
+		fmt.Fprintf(&b, "%d: [", freqNode.freq)
+		for e := freqNode.elements.Front(); e != nil; e = e.Next() {
+			if e != freqNode.elements.Front() {
+				b.WriteString(", ")
+			}
+			item := e.Value.(*CacheItem)
+			fmt.Fprintf(&b, "%v=%v", item.key, item.value)
+		}
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// FrequencyHistogram возвращает снимок распределения элементов по частотам: частота -> число элементов с ней.
+// Равномерное распределение говорит о рабочем наборе без выраженных лидеров, перекошенное - о горячих ключах
+func (c *LFUCache) FrequencyHistogram() map[int]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	histogram := make(map[int]int, c.freqNodes.Len())
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		freqNode := node.Value.(*FrequencyNode)
+		histogram[freqNode.freq] = freqNode.elements.Len()
+	}
+	return histogram
+}
+
+// KeysAtFrequency возвращает ключи с заданной частотой в порядке LRU (первым идёт кандидат на вытеснение).
+// Частоты элементов при этом не изменяются
+func (c *LFUCache) KeysAtFrequency(freq int) []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elements := c.getFrequencyList(freq)
+	if elements == nil {
+		return []interface{}{}
+	}
+
+	keys := make([]interface{}, 0, elements.Len())
+	for e := elements.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*CacheItem).key)
+	}
+	return keys
+}
+
+// evict удаляет наименее часто используемый элемент и возвращает его (nil для пустого кэша)
+func (c *LFUCache) evict(reason cache.EvictionReason) *CacheItem {
 	if c.freqNodes.Len() == 0 {
-		return
+		return nil
 	}
 
 	// Берем первый FrequencyNode (с минимальной частотой)
@@ -188,31 +1213,248 @@ func (c *LFUCache) evict() {
 	minFreqNode := minFreqNodeElem.Value.(*FrequencyNode)
 
 	// Удаляем первый элемент из списка (LRU в пределах одной частоты)
-	if minFreqNode.elements.Len() > 0 {
-		lruElem := minFreqNode.elements.Front()
-		item := lruElem.Value.(*CacheItem)
+	lruElem := minFreqNode.elements.Front()
+	item := lruElem.Value.(*CacheItem)
 
-		// Удаляем из всех структур
-		minFreqNode.elements.Remove(lruElem)
-		delete(c.items, item.key)
+	// Удаляем из всех структур
+	minFreqNode.elements.Remove(lruElem)
+	delete(c.items, c.mapKey(item.key))
+	c.totalCost -= item.cost
+	atomic.AddUint64(&c.evictions, 1)
+	c.emit(item, reason)
+
+	// Если список частот пуст, удаляем FrequencyNode
+	if minFreqNode.elements.Len() == 0 {
+		c.freqNodes.Remove(minFreqNodeElem)
+		delete(c.freqLists, minFreqNode.freq)
+	}
+	return item
+}
+
+// EvictionEvents возвращает канал событий об удалённых элементах, при первом вызове создавая его
+// с буфером cache.EventBufferSize. События о вытеснении и явном удалении (Remove, EvictN)
+// отправляются без блокировки: при заполненном буфере они отбрасываются. Clear и Load событий не порождают
+func (c *LFUCache) EvictionEvents() <-chan cache.EvictedEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		events := make(chan cache.EvictedEntry)
+		close(events)
+		return events
+	}
+	if c.events == nil {
+		c.events = make(chan cache.EvictedEntry, cache.EventBufferSize)
+	}
+	return c.events
+}
+
+// Close освобождает ресурсы кэша: закрывает канал событий удаления, а последующие вызовы
+// EvictionEvents возвращают уже закрытый канал. Фоновых горутин у кэша нет, поэтому остальные
+// операции после Close продолжают работать. Повторный вызов безопасен и возвращает nil
+func (c *LFUCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	c.stopEvents()
+	return nil
+}
+
+// StopEvictionEvents закрывает канал событий. Следующий вызов EvictionEvents создаст новый канал
+func (c *LFUCache) StopEvictionEvents() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopEvents()
+}
 
-		// Если список частот пуст, удаляем FrequencyNode
-		if minFreqNode.elements.Len() == 0 {
-			c.freqNodes.Remove(minFreqNodeElem)
-			delete(c.freqLists, minFreqNode.freq)
+// stopEvents закрывает канал событий, если он открыт
+func (c *LFUCache) stopEvents() {
+	if c.events != nil {
+		close(c.events)
+		c.events = nil
+	}
+}
+
+// emit отправляет событие об удалении элемента, если на события кто-то подписан
+func (c *LFUCache) emit(item *CacheItem, reason cache.EvictionReason) {
+	if c.events == nil {
+		return
+	}
+	select {
+	case c.events <- cache.EvictedEntry{Key: item.key, Value: item.value, Reason: reason}:
+	default:
+	}
+}
+
+// Len возвращает текущее количество элементов в кэше
+func (c *LFUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Cap возвращает текущую ёмкость кэша с учётом Resize.
+// Для кэша, ограниченного стоимостью, количество элементов не ограничено и возвращается 0
+func (c *LFUCache) Cap() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxCost > 0 {
+		return 0
+	}
+	return c.capacity
+}
+
+// snapshotEntry - сериализуемое состояние элемента
+type snapshotEntry struct {
+	Key       interface{}
+	Value     interface{}
+	Frequency int
+	Cost      int64
+}
+
+// Snapshot записывает элементы с их частотами в w через encoding/gob в порядке вытеснения.
+// Пользовательские типы ключей и значений нужно заранее зарегистрировать через gob.Register
+func (c *LFUCache) Snapshot(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]snapshotEntry, 0, len(c.items))
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			item := e.Value.(*CacheItem)
+			entries = append(entries, snapshotEntry{
+				Key:       item.key,
+				Value:     item.value,
+				Frequency: item.frequency,
+				Cost:      item.cost,
+			})
 		}
 	}
+	return gob.NewEncoder(w).Encode(entries)
+}
+
+// Load заменяет содержимое кэша снимком из Snapshot, восстанавливая частоты и порядок вытеснения.
+// Если элементов больше ёмкости или их суммарная стоимость превышает maxCost, отбрасываются первые
+// кандидаты на вытеснение; элементы, которые PutWithCost отклонил бы по стоимости, не загружаются
+func (c *LFUCache) Load(r io.Reader) error {
+	var entries []snapshotEntry
+	if err := gob.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(entries) > c.capacity {
+		entries = entries[len(entries)-c.capacity:]
+	}
+	if c.maxCost > 0 {
+		entries = fitCost(entries, c.maxCost)
+	}
+
+	c.clear()
+	for _, entry := range entries {
+		freq := c.capFrequency(entry.Frequency)
+		item := &CacheItem{
+			key:       entry.Key,
+			value:     entry.Value,
+			frequency: freq,
+			cost:      entry.Cost,
+			seq:       c.nextSeq,
+		}
+		c.nextSeq++
+		c.items[c.mapKey(entry.Key)] = c.addToFrequencyList(freq, item)
+		c.totalCost += entry.Cost
+	}
+	c.recomputeMinFreq()
+	return nil
+}
+
+// fitCost отбрасывает элементы снимка с недопустимой стоимостью, а затем первые кандидаты на вытеснение,
+// пока суммарная стоимость оставшихся превышает maxCost
+func fitCost(entries []snapshotEntry, maxCost int64) []snapshotEntry {
+	fitting := entries[:0:0]
+	var total int64
+	for _, entry := range entries {
+		if entry.Cost < 0 || entry.Cost > maxCost {
+			continue
+		}
+		fitting = append(fitting, entry)
+		total += entry.Cost
+	}
+	for total > maxCost {
+		total -= fitting[0].Cost
+		fitting = fitting[1:]
+	}
+	return fitting
+}
+
+// TotalCost возвращает суммарную стоимость элементов в кэше
+func (c *LFUCache) TotalCost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.totalCost
+}
+
+// Evictions возвращает количество элементов, вытесненных политикой или через EvictN
+func (c *LFUCache) Evictions() uint64 {
+	return atomic.LoadUint64(&c.evictions)
+}
+
+// Stats возвращает снимок счётчиков попаданий, промахов и вытеснений
+func (c *LFUCache) Stats() Stats {
+	return Stats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Evictions: atomic.LoadUint64(&c.evictions),
+	}
+}
+
+// ResetStats обнуляет счётчики статистики
+func (c *LFUCache) ResetStats() {
+	atomic.StoreUint64(&c.hits, 0)
+	atomic.StoreUint64(&c.misses, 0)
+	atomic.StoreUint64(&c.evictions, 0)
 }
 
 // Size возвращает текущий размер кэша
+//
+// Deprecated: используйте Len, имя совпадает с LRU кэшем
 func (c *LFUCache) Size() int {
-	return len(c.items)
+	return c.Len()
 }
 
 // Clear очищает кэш
 func (c *LFUCache) Clear() {
-	c.items = make(map[interface{}]*list.Element)
-	c.freqLists = make(map[int]*list.Element)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clear()
+}
+
+// clear удаляет все элементы без событий удаления
+func (c *LFUCache) clear() {
+	c.items = make(map[interface{}]*list.Element, c.sizeHint())
+	c.freqLists = make(map[int]*list.Element, c.maxFreq)
 	c.freqNodes.Init()
 	c.minFreq = 0
+	c.totalCost = 0
+	c.expiredBucket = 0
+}
+
+// Flush удаляет из кэша все элементы и возвращает их в порядке вытеснения, как Keys,
+// например, чтобы при остановке сохранить оставшиеся элементы в другом месте.
+// Как и Clear, работает и в режиме только для чтения. При notify=true о каждом элементе
+// отправляется событие удаления с причиной cache.EvictedManual
+func (c *LFUCache) Flush(notify bool) []cache.KV {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]cache.KV, 0, len(c.items))
+	for node := c.freqNodes.Front(); node != nil; node = node.Next() {
+		for e := node.Value.(*FrequencyNode).elements.Front(); e != nil; e = e.Next() {
+			item := e.Value.(*CacheItem)
+			entries = append(entries, cache.KV{Key: item.key, Value: item.value})
+			if notify {
+				c.emit(item, cache.EvictedManual)
+			}
+		}
+	}
+	c.clear()
+	return entries
 }
@@ -1,8 +1,14 @@
 package lfu
 
 import (
+	"LRU_cache/pkg/cache"
+	"LRU_cache/pkg/cache/lru"
+	"bytes"
 	"container/list"
+	"io"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,6 +24,50 @@ func TestNewLFUCache_InvalidCapacity(t *testing.T) {
 	}, "Expected panic for negative capacity")
 }
 
+// TestZeroCapacity_Rejected фиксирует, что LFU кэш не бывает нулевой ёмкости ни при создании, ни при Resize
+func TestZeroCapacity_Rejected(t *testing.T) {
+	assert.Panics(t, func() { NewLFUCacheWindowed(0, time.Minute) })
+	assert.Panics(t, func() { NewLFUCacheWithMaxCost(0) })
+
+	cache := NewLFUCache(1)
+	assert.Panics(t, func() { cache.Resize(0) })
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	assert.Equal(t, 1, cache.Len(), "The smallest cache still stores one entry")
+}
+
+// TestZeroCapacity_AllowZero проверяет, что NewLFUCacheAllowZero ведёт себя как LRU нулевой ёмкости
+func TestZeroCapacity_AllowZero(t *testing.T) {
+	cache := NewLFUCacheAllowZero(0)
+
+	ok := cache.Add("key1", "value1")
+	assert.True(t, ok, "Add should return true even with capacity 0")
+	cache.Put("key2", "value2")
+	assert.True(t, cache.PutWithCost("key3", "value3", 1))
+
+	_, ok = cache.Get("key1")
+	assert.False(t, ok, "No element should be stored when capacity is 0")
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 0, cache.freqNodes.Len(), "Frequency list should remain empty")
+
+	assert.Panics(t, func() { NewLFUCacheAllowZero(-1) })
+	assert.Equal(t, 2, NewLFUCacheAllowZero(2).capacity)
+}
+
+// TestNewLFUCacheE проверяет, что NewLFUCacheE возвращает ошибку вместо panic
+func TestNewLFUCacheE(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		cache, err := NewLFUCacheE(capacity)
+		assert.Error(t, err, "Capacity %d should be rejected", capacity)
+		assert.Nil(t, cache)
+	}
+
+	cache, err := NewLFUCacheE(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cache.capacity)
+}
+
 // TestNewLFUCache_ValidCapacity проверяет создание кэша с корректной ёмкостью
 func TestNewLFUCache_ValidCapacity(t *testing.T) {
 	cache := NewLFUCache(3)
@@ -40,11 +90,11 @@ func TestGet_NonExistentKey(t *testing.T) {
 func TestPut_Get_Simple(t *testing.T) {
 	cache := NewLFUCache(2)
 	cache.Put("key1", "value1")
+	assert.Equal(t, 1, cache.minFreq, "minFreq should be 1 after first insert")
 
 	val, ok := cache.Get("key1")
 	assert.True(t, ok, "Key should exist")
 	assert.Equal(t, "value1", val, "Returned value should match")
-	assert.Equal(t, 1, cache.minFreq, "minFreq should be 1 after first insert")
 }
 
 // TestPut_ExistingKey обновляет существующий ключ
@@ -56,7 +106,316 @@ func TestPut_ExistingKey(t *testing.T) {
 	val, ok := cache.Get("key1")
 	assert.True(t, ok, "Key should exist")
 	assert.Equal(t, "value2", val, "Value should be updated")
-	assert.Equal(t, 1, cache.minFreq, "minFreq should still be 1 (same frequency)")
+	assert.Equal(t, 3, cache.minFreq, "minFreq should follow the only key: Put update and Get both increment")
+}
+
+// TestMaxFrequency проверяет, что частота упирается в предел, а число узлов частот ограничено
+func TestMaxFrequency(t *testing.T) {
+	cache := NewLFUCache(10, WithMaxFrequency(3))
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+		for j := 0; j < i*5; j++ {
+			cache.Get(i)
+		}
+	}
+
+	assert.Equal(t, 3, cache.items[9].Value.(*CacheItem).frequency, "Frequency should saturate at the cap")
+	assert.LessOrEqual(t, cache.freqNodes.Len(), 3, "Node count should be bounded by the cap")
+
+	// Обращение к элементу с предельной частотой переносит его в конец списка
+	capped := NewLFUCache(2, WithMaxFrequency(2))
+	capped.Put("a", 1)
+	capped.Get("a")
+	capped.Put("b", 2)
+	capped.Get("b")
+	capped.Get("a")
+	capped.Put("c", 3)
+	_, ok := capped.Get("b")
+	assert.False(t, ok, "Least recently used key at the cap should be evicted")
+	_, ok = capped.Get("a")
+	assert.True(t, ok)
+
+	assert.Panics(t, func() { WithMaxFrequency(0) })
+}
+
+// TestTouch проверяет, что Touch повышает частоту без чтения значения
+func TestTouch(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+
+	assert.True(t, cache.Touch("key1"))
+	assert.False(t, cache.Touch("absent"))
+	assert.Equal(t, 2, cache.items["key1"].Value.(*CacheItem).frequency)
+
+	cache.Put("key3", "value3")
+	_, ok := cache.Get("key2")
+	assert.False(t, ok, "Untouched key should be evicted")
+	_, ok = cache.Get("key1")
+	assert.True(t, ok, "Touched key should survive")
+}
+
+// TestReplace проверяет, что Replace обновляет только существующий ключ и повышает его частоту
+func TestReplace(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("key1", "value1")
+
+	assert.True(t, cache.Replace("key1", "new"))
+	item := cache.items["key1"].Value.(*CacheItem)
+	assert.Equal(t, "new", item.value)
+	assert.Equal(t, 2, item.frequency, "Replace should bump frequency like Put")
+
+	assert.False(t, cache.Replace("absent", "value"), "Absent key should be rejected")
+	assert.Equal(t, 1, cache.Len())
+}
+
+// TestKeyFunc_ByteSliceKeys проверяет, что WithKeyFunc позволяет использовать несравнимые ключи []byte
+func TestKeyFunc_ByteSliceKeys(t *testing.T) {
+	assert.Panics(t, func() { NewLFUCache(2).Put([]byte("key1"), "value1") }, "[]byte keys are not comparable")
+
+	cache := NewLFUCache(2, WithKeyFunc(func(key interface{}) string { return string(key.([]byte)) }))
+	cache.Put([]byte("key1"), "value1")
+	assert.False(t, cache.Add([]byte("key1"), "updated"), "Equal byte slices should be the same key")
+	cache.Put([]byte("key2"), "value2")
+
+	val, ok := cache.Get([]byte("key1"))
+	assert.True(t, ok)
+	assert.Equal(t, "updated", val)
+
+	key, _, evicted := cache.PutEvict([]byte("key3"), "value3")
+	assert.True(t, evicted)
+	assert.Equal(t, []byte("key2"), key, "Original key should be kept on the item")
+	assert.ElementsMatch(t, []interface{}{[]byte("key1"), []byte("key3")}, cache.Keys())
+
+	_, _, evicted = cache.PutEvict([]byte("key3"), "updated3")
+	assert.False(t, evicted, "Updating an existing key should not evict")
+
+	assert.True(t, cache.Remove([]byte("key1")))
+	assert.Equal(t, 1, cache.Len())
+}
+
+// TestOnHitOnMiss проверяет вызовы хуков на попаданиях и промахах Get
+func TestOnHitOnMiss(t *testing.T) {
+	hits, misses := 0, 0
+	cache := NewLFUCache(2,
+		WithOnHit(func(key interface{}) { hits++ }),
+		WithOnMiss(func(key interface{}) { misses++ }),
+	)
+	cache.Put("key1", "value1")
+
+	cache.Get("key1")
+	cache.Get("absent")
+	cache.Get("key1")
+	cache.Get("other")
+	cache.Touch("key1")
+
+	assert.Equal(t, 2, hits)
+	assert.Equal(t, 2, misses)
+}
+
+// TestFlush проверяет, что Flush возвращает элементы в порядке вытеснения и оставляет кэш пустым
+func TestFlush(t *testing.T) {
+	c := NewLFUCache(3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+	events := c.EvictionEvents()
+
+	entries := c.Flush(true)
+	assert.Equal(t, []cache.KV{{Key: "b", Value: 2}, {Key: "c", Value: 3}, {Key: "a", Value: 1}}, entries)
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, 0, c.MinFrequency())
+	assert.Len(t, events, 3, "Flush with notify should emit an event per entry")
+
+	c.Put("d", 4)
+	_, ok := c.Get("d")
+	assert.True(t, ok, "Flushed cache should accept new keys")
+}
+
+// TestClose проверяет, что Close закрывает канал событий и повторный вызов безопасен
+func TestClose(t *testing.T) {
+	var _ io.Closer = (*LFUCache)(nil)
+
+	c := NewLFUCache(1)
+	events := c.EvictionEvents()
+	assert.NoError(t, c.Close())
+	assert.NoError(t, c.Close(), "Double close should be safe")
+
+	_, open := <-events
+	assert.False(t, open, "Close should close the event channel")
+	_, open = <-c.EvictionEvents()
+	assert.False(t, open, "Subscribing after Close should return a closed channel")
+
+	c.Put("a", 1)
+	c.Put("b", 2) // вытеснение после Close не отправляет событий
+	val, ok := c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+}
+
+// TestNilValue проверяет, что сохранённый nil отличается от промаха
+func TestNilValue(t *testing.T) {
+	c := NewLFUCache(2)
+	c.Put("key1", nil)
+
+	val, ok := c.Get("key1")
+	assert.True(t, ok)
+	assert.Nil(t, val)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+}
+
+// TestCap проверяет, что Cap возвращает ёмкость из конструктора и обновляется после Resize
+func TestCap(t *testing.T) {
+	c := NewLFUCache(5)
+	assert.Equal(t, 5, c.Cap())
+
+	c.Resize(2)
+	assert.Equal(t, 2, c.Cap())
+
+	assert.Equal(t, 0, NewLFUCacheWithMaxCost(10).Cap())
+}
+
+var _ cache.EntryLister = (*LFUCache)(nil)
+
+// TestMerge_Disjoint проверяет, что Merge без общих ключей добавляет элементы other с учётом ёмкости
+func TestMerge_Disjoint(t *testing.T) {
+	c := NewLFUCache(3)
+	c.Put("a", 1)
+	c.Get("a")
+
+	other := NewLFUCache(3)
+	other.Put("x", 10)
+	other.Put("y", 20)
+	other.Put("z", 30)
+
+	c.Merge(other, func(key, a, b interface{}) interface{} {
+		t.Fatalf("resolve should not be called for disjoint caches, got key %v", key)
+		return nil
+	})
+	assert.Equal(t, []interface{}{"y", "z", "a"}, c.Keys(), "x should be evicted as the least frequent")
+}
+
+// TestMerge_Conflict проверяет, что для общих ключей сохраняется результат resolve
+func TestMerge_Conflict(t *testing.T) {
+	c := NewLFUCache(3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+
+	other := NewLFUCache(3)
+	other.Put("b", 20)
+	other.Put("c", 30)
+
+	c.Merge(other, func(key, a, b interface{}) interface{} {
+		assert.Equal(t, "b", key)
+		return a.(int) + b.(int)
+	})
+
+	assert.Equal(t, 22, c.items["b"].Value.(*CacheItem).value)
+	assert.Equal(t, 2, c.items["b"].Value.(*CacheItem).frequency)
+	assert.Equal(t, 3, c.Len())
+}
+
+// TestFrequencyHistogram проверяет распределение элементов по частотам
+func TestFrequencyHistogram(t *testing.T) {
+	c := NewLFUCache(5)
+	assert.Empty(t, c.FrequencyHistogram())
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		c.Put(key, key)
+	}
+	c.Get("a")
+	c.Get("b")
+	c.Get("a")
+	c.Get("a")
+
+	assert.Equal(t, map[int]int{1: 3, 2: 1, 4: 1}, c.FrequencyHistogram())
+}
+
+// TestEntries проверяет порядок вытеснения и частоты в снимке элементов
+func TestEntries(t *testing.T) {
+	c := NewLFUCache(3)
+	assert.Empty(t, c.Entries())
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("c")
+
+	assert.Equal(t, []cache.Entry{
+		{Key: "b", Value: 2, Rank: 1},
+		{Key: "c", Value: 3, Rank: 2},
+		{Key: "a", Value: 1, Rank: 3},
+	}, c.Entries())
+	assert.Equal(t, 3, c.items["a"].Value.(*CacheItem).frequency, "Entries should not change frequencies")
+}
+
+// TestPutEvict проверяет, что PutEvict возвращает вытесненный элемент только при переполнении
+func TestPutEvict(t *testing.T) {
+	cache := NewLFUCache(2)
+
+	_, _, evicted := cache.PutEvict("key1", "value1")
+	assert.False(t, evicted, "No eviction while there is room")
+	cache.PutEvict("key2", "value2")
+
+	_, _, evicted = cache.PutEvict("key1", "updated")
+	assert.False(t, evicted, "Updating an existing key should not evict")
+
+	key, value, evicted := cache.PutEvict("key3", "value3")
+	assert.True(t, evicted)
+	assert.Equal(t, "key2", key, "The least frequently used entry should be evicted")
+	assert.Equal(t, "value2", value)
+
+	cache.EvictN(1)
+	_, _, evicted = cache.PutEvict("key4", "value4")
+	assert.False(t, evicted, "Earlier evictions should not be reported")
+}
+
+// TestPutEvict_Concurrent проверяет, что конкурентные PutEvict получают каждый свою жертву
+func TestPutEvict_Concurrent(t *testing.T) {
+	cache := NewLFUCache(4)
+	var (
+		mu      sync.Mutex
+		victims = map[interface{}]int{}
+		wg      sync.WaitGroup
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if key, _, evicted := cache.PutEvict(g*1000+i, i); evicted {
+					mu.Lock()
+					victims[key]++
+					mu.Unlock()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Len(t, victims, int(cache.Evictions()), "Every eviction should be reported exactly once")
+	for key, n := range victims {
+		assert.Equal(t, 1, n, "Victim %v reported more than once", key)
+	}
+}
+
+// TestPutIfAbsent проверяет, что существующий ключ не перезаписывается и не повышает частоту
+func TestPutIfAbsent(t *testing.T) {
+	cache := NewLFUCache(2)
+
+	actual, loaded := cache.PutIfAbsent("key1", "value1")
+	assert.False(t, loaded, "Absent key should be stored")
+	assert.Equal(t, "value1", actual)
+
+	actual, loaded = cache.PutIfAbsent("key1", "other")
+	assert.True(t, loaded, "Existing key should be loaded")
+	assert.Equal(t, "value1", actual, "Existing value should not be replaced")
+	assert.Equal(t, 1, cache.items["key1"].Value.(*CacheItem).frequency, "Frequency should not change")
 }
 
 // TestPut_TriggerIncrementFrequency проверяет, что Get увеличивает частоту
@@ -115,8 +474,8 @@ func TestEvict_UpdateMinFreq(t *testing.T) {
 	cache.Put("key2", "value2")
 	cache.Get("key1") // freq: key1=2, key2=1
 
-	cache.Put("key3", "value3") // evict key2 -> minFreq должен стать 2
-	assert.Equal(t, 2, cache.minFreq, "minFreq should update to 2 after evicting last freq=1")
+	cache.Put("key3", "value3") // evict key2, key3 вставляется с freq=1
+	assert.Equal(t, 1, cache.minFreq, "minFreq should be 1 again after inserting key3")
 }
 
 // TestSize проверяет корректность подсчёта размера
@@ -134,6 +493,7 @@ func TestSize(t *testing.T) {
 	cache.Put("k3", "v3")
 	cache.Put("k4", "v4") // evict one
 	assert.Equal(t, 3, cache.Size(), "Size should be capped at capacity")
+	assert.Equal(t, cache.Size(), cache.Len(), "Len and Size should agree")
 }
 
 // TestClear очищает кэш
@@ -213,3 +573,1050 @@ func TestGet_AfterEvict(t *testing.T) {
 	assert.Nil(t, val)
 	assert.False(t, ok, "Evicted key should not be retrievable")
 }
+
+// TestKeysAtFrequency проверяет выборку ключей по частоте в порядке LRU
+func TestKeysAtFrequency(t *testing.T) {
+	cache := NewLFUCache(5)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	cache.Get("c") // c.freq=2
+	cache.Get("a") // a.freq=2
+	cache.Get("d")
+	cache.Get("d") // d.freq=3
+
+	assert.Equal(t, []interface{}{"b"}, cache.KeysAtFrequency(1))
+	assert.Equal(t, []interface{}{"c", "a"}, cache.KeysAtFrequency(2), "Keys should be in LRU order")
+	assert.Equal(t, []interface{}{"d"}, cache.KeysAtFrequency(3))
+	assert.Empty(t, cache.KeysAtFrequency(4), "No keys expected for unused frequency")
+
+	// Выборка не должна менять частоты
+	assert.Equal(t, []interface{}{"c", "a"}, cache.KeysAtFrequency(2))
+	assert.Equal(t, 1, cache.minFreq)
+}
+
+// TestRemove_MinFreqOnlyKey проверяет удаление единственного ключа с минимальной частотой
+func TestRemove_MinFreqOnlyKey(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Get("key2")
+	cache.Get("key2") // key1.freq=1, key2.freq=3
+
+	ok := cache.Remove("key1")
+	assert.True(t, ok, "Remove should return true for existing key")
+	assert.Equal(t, 3, cache.minFreq, "minFreq should move to the next populated frequency")
+	assert.Nil(t, cache.getFrequencyList(1), "Empty frequency node should be removed")
+	assert.Equal(t, 1, cache.Size())
+
+	_, found := cache.Get("key1")
+	assert.False(t, found, "Removed key should not be retrievable")
+
+	assert.True(t, cache.Remove("key2"))
+	assert.Equal(t, 0, cache.minFreq, "minFreq should reset when the cache becomes empty")
+	assert.Equal(t, 0, cache.freqNodes.Len())
+}
+
+// TestRemove_NonMinKey проверяет удаление ключа, не влияющего на minFreq
+func TestRemove_NonMinKey(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Get("key2") // key1.freq=1, key2.freq=2
+
+	assert.True(t, cache.Remove("key2"))
+	assert.Equal(t, 1, cache.minFreq, "minFreq should stay unchanged")
+	assert.Nil(t, cache.getFrequencyList(2))
+	assert.Equal(t, []interface{}{"key1"}, cache.KeysAtFrequency(1))
+}
+
+// TestRemove_NonExistent проверяет удаление отсутствующего ключа
+func TestRemove_NonExistent(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("key1", "value1")
+
+	assert.False(t, cache.Remove("unknown"), "Remove should return false for absent key")
+	assert.Equal(t, 1, cache.Size())
+}
+
+// TestResize_GrowThenShrink проверяет увеличение и уменьшение ёмкости
+func TestResize_GrowThenShrink(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	assert.Equal(t, 0, cache.Resize(4), "Growing should not evict")
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	assert.Equal(t, 4, cache.Len())
+
+	cache.Get("a")
+	cache.Get("a") // a.freq=3
+	cache.Get("c") // c.freq=2
+	// b.freq=1, d.freq=1 (b старше)
+
+	evicted := cache.Resize(2)
+	assert.Equal(t, 2, evicted, "Two entries should be evicted")
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, 2, cache.minFreq, "minFreq should move to the lowest remaining frequency")
+
+	_, okB := cache.items["b"]
+	_, okD := cache.items["d"]
+	assert.False(t, okB, "b should be evicted")
+	assert.False(t, okD, "d should be evicted")
+	assert.Equal(t, []interface{}{"c"}, cache.KeysAtFrequency(2))
+	assert.Equal(t, []interface{}{"a"}, cache.KeysAtFrequency(3))
+}
+
+// TestResize_InvalidCapacity проверяет отказ от некорректной ёмкости
+func TestResize_InvalidCapacity(t *testing.T) {
+	cache := NewLFUCache(2)
+
+	assert.Panics(t, func() {
+		cache.Resize(0)
+	}, "Expected panic for capacity <= 0")
+}
+
+// TestFrequencyRateLimit проверяет ограничение роста частоты в пределах интервала
+func TestFrequencyRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLFUCache(2,
+		WithClock(func() time.Time { return now }),
+		WithFrequencyRateLimit(2, time.Minute),
+	)
+	cache.Put("key1", "value1") // freq=1
+
+	for i := 0; i < 10; i++ {
+		val, ok := cache.Get("key1")
+		assert.True(t, ok, "Value should still be served when rate limited")
+		assert.Equal(t, "value1", val)
+	}
+	assert.Equal(t, 3, cache.items["key1"].Value.(*CacheItem).frequency,
+		"Frequency should rise by at most 2 within the interval")
+
+	now = now.Add(30 * time.Second)
+	cache.Get("key1")
+	assert.Equal(t, 3, cache.items["key1"].Value.(*CacheItem).frequency,
+		"Frequency should stay capped until the interval elapses")
+
+	now = now.Add(31 * time.Second)
+	for i := 0; i < 5; i++ {
+		cache.Get("key1")
+	}
+	assert.Equal(t, 5, cache.items["key1"].Value.(*CacheItem).frequency,
+		"Frequency should rise again after the interval elapsed")
+	assert.Equal(t, 5, cache.minFreq)
+}
+
+// TestAdd_CacheInterface проверяет семантику Add из интерфейса cache.Cache
+func TestAdd_CacheInterface(t *testing.T) {
+	cache := NewLFUCache(2)
+
+	assert.True(t, cache.Add("key1", "value1"), "Add should return true for new key")
+	assert.False(t, cache.Add("key1", "value2"), "Add should return false for existing key")
+
+	val, _ := cache.Get("key1")
+	assert.Equal(t, "value2", val, "Add should update existing value")
+}
+
+// TestCacheInterface_Interchangeable проверяет, что LRU и LFU взаимозаменяемы через cache.Cache
+func TestCacheInterface_Interchangeable(t *testing.T) {
+	caches := []cache.Cache{lru.NewLRUCache(2), NewLFUCache(2)}
+
+	for _, c := range caches {
+		assert.True(t, c.Add("key1", "value1"))
+		assert.True(t, c.Add("key2", "value2"))
+		assert.False(t, c.Add("key1", "value1"), "Duplicate Add should return false")
+
+		val, ok := c.Get("key1")
+		assert.True(t, ok)
+		assert.Equal(t, "value1", val)
+
+		assert.True(t, c.Add("key3", "value3")) // вытесняется key2
+		_, ok = c.Get("key2")
+		assert.False(t, ok, "key2 should be evicted")
+
+		assert.True(t, c.Remove("key1"))
+		assert.False(t, c.Remove("key1"))
+	}
+}
+
+// TestCacheInterface_MissReturnsNil проверяет единое значение промаха для обоих кэшей
+func TestCacheInterface_MissReturnsNil(t *testing.T) {
+	caches := []cache.Cache{lru.NewLRUCache(2), NewLFUCache(2)}
+
+	for _, c := range caches {
+		val, ok := c.Get("unknown")
+		assert.Nil(t, val, "Miss should return nil value")
+		assert.False(t, ok, "Miss should return false")
+	}
+}
+
+// TestStats проверяет счётчики попаданий, промахов и вытеснений
+func TestStats(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("k1", "v1")
+	cache.Put("k2", "v2")
+	cache.Get("k1")       // hit
+	cache.Get("missing")  // miss
+	cache.Put("k3", "v3") // вытесняется k2
+	cache.Put("k4", "v4") // вытесняется k3
+	cache.Put("k1", "v1") // обновление, без вытеснения
+	cache.Get("k2")       // miss
+	cache.Get("k4")       // hit
+
+	assert.Equal(t, Stats{Hits: 2, Misses: 2, Evictions: 2}, cache.Stats())
+
+	cache.Resize(1)
+	assert.Equal(t, uint64(3), cache.Stats().Evictions, "Resize evictions should be counted")
+
+	cache.ResetStats()
+	assert.Equal(t, Stats{}, cache.Stats(), "Stats should be zero after reset")
+}
+
+// TestKeys проверяет порядок ключей по частоте и LRU внутри частоты
+func TestKeys(t *testing.T) {
+	cache := NewLFUCache(5)
+	assert.Empty(t, cache.Keys())
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	cache.Get("b")
+	cache.Get("b") // b.freq=3
+	cache.Get("d") // d.freq=2
+	cache.Get("a") // a.freq=2 (после d)
+
+	assert.Equal(t, []interface{}{"c", "d", "a", "b"}, cache.Keys())
+	assert.Equal(t, []interface{}{"c", "d", "a", "b"}, cache.Keys(), "Keys should not change frequencies")
+}
+
+// TestRange проверяет обход всех пар без изменения частот
+func TestRange(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+
+	collected := map[interface{}]interface{}{}
+	var order []interface{}
+	cache.Range(func(key, value interface{}) bool {
+		collected[key] = value
+		order = append(order, key)
+		return true
+	})
+	assert.Equal(t, map[interface{}]interface{}{"a": 1, "b": 2, "c": 3}, collected)
+	assert.Equal(t, []interface{}{"b", "c", "a"}, order)
+	assert.Equal(t, 2, cache.items["a"].Value.(*CacheItem).frequency, "Range should not change frequency")
+	assert.Equal(t, 1, cache.minFreq)
+}
+
+// TestRange_EarlyStop проверяет досрочную остановку обхода
+func TestRange_EarlyStop(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+
+	visited := 0
+	cache.Range(func(key, value interface{}) bool {
+		visited++
+		return false
+	})
+	assert.Equal(t, 1, visited)
+}
+
+// TestDecay проверяет пропорциональное снижение частот с сохранением порядка
+func TestDecay(t *testing.T) {
+	cache := NewLFUCache(4)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	for i := 0; i < 9; i++ {
+		cache.Get("a") // a.freq=10
+	}
+	for i := 0; i < 5; i++ {
+		cache.Get("b") // b.freq=6
+	}
+	cache.Get("c") // c.freq=2, d.freq=1
+
+	before := cache.Keys()
+	cache.Decay(0.5)
+
+	freq := func(key string) int {
+		return cache.items[key].Value.(*CacheItem).frequency
+	}
+	assert.Equal(t, 5, freq("a"))
+	assert.Equal(t, 3, freq("b"))
+	assert.Equal(t, 1, freq("c"))
+	assert.Equal(t, 1, freq("d"), "Frequency should not drop below 1")
+	assert.Equal(t, 1, cache.minFreq, "minFreq should be recomputed")
+	assert.Equal(t, before, cache.Keys(), "Relative eviction order should be preserved")
+	assert.Equal(t, []interface{}{"d", "c"}, cache.KeysAtFrequency(1))
+
+	// После затухания новый ключ с частотой 1 конкурирует со старыми
+	cache.Put("e", 5)
+	_, ok := cache.items["d"]
+	assert.False(t, ok, "d should be evicted first after decay")
+}
+
+// TestDecay_UpdatesMinFreq проверяет пересчёт minFreq, когда все частоты высокие
+func TestDecay_UpdatesMinFreq(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("a", 1)
+	for i := 0; i < 7; i++ {
+		cache.Get("a") // a.freq=8
+	}
+	assert.Equal(t, 8, cache.minFreq)
+
+	cache.Decay(0.5)
+	assert.Equal(t, 4, cache.minFreq)
+
+	assert.Panics(t, func() { cache.Decay(0) })
+	assert.Panics(t, func() { cache.Decay(1.5) })
+}
+
+// TestWindowed_StaleHitsLoseToCurrentHits проверяет, что давно популярный ключ уступает популярному сейчас
+func TestWindowed_StaleHitsLoseToCurrentHits(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := WithClock(func() time.Time { return now })
+
+	windowed := NewLFUCacheWindowed(2, time.Minute, clock)
+	lifetime := NewLFUCache(2, clock)
+	for _, cache := range []*LFUCache{windowed, lifetime} {
+		cache.Put("old", 1)
+		for i := 0; i < 5; i++ {
+			cache.Get("old") // популярен в прошлом
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	for _, cache := range []*LFUCache{windowed, lifetime} {
+		cache.Put("new", 2)
+		cache.Get("new")
+		cache.Get("new") // популярен сейчас
+		cache.Put("next", 3)
+	}
+
+	_, ok := windowed.items["old"]
+	assert.False(t, ok, "Windowed LFU should evict the key hot only in the past")
+	_, ok = windowed.items["new"]
+	assert.True(t, ok, "Windowed LFU should keep the currently hot key")
+	assert.Equal(t, 3, windowed.items["new"].Value.(*CacheItem).frequency)
+
+	_, ok = lifetime.items["old"]
+	assert.True(t, ok, "Lifetime LFU keeps the key with the larger total count")
+}
+
+// TestWindowed_BoundedAccesses проверяет, что история обращений горячего ключа не растёт без ограничения
+func TestWindowed_BoundedAccesses(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLFUCacheWindowed(2, time.Minute, WithClock(func() time.Time { return now }))
+	cache.Put("hot", 1)
+	for i := 0; i < 100000; i++ {
+		cache.Get("hot")
+		now = now.Add(time.Millisecond)
+	}
+
+	item := cache.items["hot"].Value.(*CacheItem)
+	assert.LessOrEqual(t, len(item.accesses), windowBuckets+1)
+}
+
+// TestWindowed_ExpireMovesOnlyChanged проверяет, что пересчёт частот не трогает элементы с прежней частотой
+func TestWindowed_ExpireMovesOnlyChanged(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLFUCacheWindowed(3, time.Minute, WithClock(func() time.Time { return now }))
+	cache.Put("old", 1)
+	cache.Get("old") // old: 2 обращения, скоро выйдут из окна
+
+	now = now.Add(50 * time.Second)
+	cache.Put("a", 2)
+	cache.Get("a")
+	cache.Put("b", 3)
+	elemA := cache.items["a"]
+
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, 1, cache.EvictN(1))
+
+	_, ok := cache.items["b"]
+	assert.False(t, ok, "old drops to frequency 1 and is placed behind b in eviction order")
+	assert.Same(t, elemA, cache.items["a"], "Item with unchanged frequency should not be moved")
+	assert.Equal(t, 1, cache.items["old"].Value.(*CacheItem).frequency, "Stale accesses should be dropped")
+	assert.Equal(t, []interface{}{"old", "a"}, cache.Keys())
+}
+
+// TestWindowed_FreshHitsCount проверяет, что обращения внутри окна продолжают учитываться
+func TestWindowed_FreshHitsCount(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLFUCacheWindowed(2, time.Minute, WithClock(func() time.Time { return now }))
+	cache.Put("a", 1)
+	cache.Get("a")
+	cache.Get("a") // a: 3 обращения
+
+	now = now.Add(30 * time.Second)
+	cache.Put("b", 2)
+	cache.Get("b") // b: 2 обращения
+
+	now = now.Add(20 * time.Second)
+	cache.Put("c", 3) // окно ещё покрывает все обращения, вытесняется b
+
+	_, ok := cache.items["b"]
+	assert.False(t, ok, "b has fewer fresh accesses and should be evicted")
+	assert.Equal(t, 3, cache.items["a"].Value.(*CacheItem).frequency)
+}
+
+// TestPutWithCost_MultiEviction проверяет, что крупный элемент вытесняет несколько мелких редко используемых
+func TestPutWithCost_MultiEviction(t *testing.T) {
+	cache := NewLFUCacheWithMaxCost(10)
+	cache.PutWithCost("hot", "H", 4)
+	cache.PutWithCost("a", "A", 2)
+	cache.PutWithCost("b", "B", 2)
+	cache.PutWithCost("c", "C", 2)
+	cache.Get("hot")
+	cache.Get("c") // a, b с freq=1 - первые кандидаты
+
+	assert.True(t, cache.PutWithCost("big", "BIG", 4))
+	assert.Equal(t, []interface{}{"big", "hot", "c"}, cache.Keys(), "a and b should be evicted")
+	assert.Equal(t, int64(10), cache.TotalCost())
+	assert.Equal(t, 1, cache.minFreq, "minFreq should be consistent after multi-evict")
+	assert.Equal(t, uint64(2), cache.Stats().Evictions)
+
+	// Дальше вытесняется big (freq=1), а не более частые c и hot
+	cache.PutWithCost("d", "D", 3)
+	_, ok := cache.items["big"]
+	assert.False(t, ok)
+	assert.Equal(t, int64(9), cache.TotalCost())
+}
+
+// TestPutWithCost_UpdateKeepsItem проверяет, что подорожавший элемент вытесняет других, но не себя
+func TestPutWithCost_UpdateKeepsItem(t *testing.T) {
+	cache := NewLFUCacheWithMaxCost(10)
+	cache.PutWithCost("a", "A", 3)
+	cache.PutWithCost("b", "B", 3)
+	cache.PutWithCost("c", "C", 3)
+
+	assert.True(t, cache.PutWithCost("a", "A2", 8))
+	assert.Equal(t, []interface{}{"a"}, cache.Keys())
+	assert.Equal(t, int64(8), cache.TotalCost())
+	assert.Equal(t, 2, cache.minFreq)
+}
+
+// TestPutWithCost_UpdateVsReject проверяет, что результат PutWithCost означает принятие записи
+// и отличает обновление существующего ключа от отказа
+func TestPutWithCost_UpdateVsReject(t *testing.T) {
+	cache := NewLFUCacheWithMaxCost(10)
+	assert.True(t, cache.PutWithCost("a", "A", 5), "New key should be accepted")
+
+	assert.True(t, cache.PutWithCost("a", "A2", 6), "Update should be accepted")
+	val, _ := cache.Get("a")
+	assert.Equal(t, "A2", val)
+	assert.Equal(t, int64(6), cache.TotalCost())
+
+	assert.False(t, cache.PutWithCost("a", "A3", 11), "Oversized update should be rejected")
+	val, _ = cache.Get("a")
+	assert.Equal(t, "A2", val)
+	assert.Equal(t, int64(6), cache.TotalCost())
+}
+
+// TestPutWithCost_RejectOversized проверяет отказ для элемента дороже лимита
+func TestPutWithCost_RejectOversized(t *testing.T) {
+	cache := NewLFUCacheWithMaxCost(10)
+	cache.PutWithCost("a", "A", 5)
+
+	assert.False(t, cache.PutWithCost("huge", "HUGE", 11))
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, int64(5), cache.TotalCost())
+
+	cache.Remove("a")
+	assert.Equal(t, int64(0), cache.TotalCost(), "Remove should release cost")
+}
+
+// TestPutWithCost_RejectNegative проверяет отказ для элемента с отрицательной стоимостью
+func TestPutWithCost_RejectNegative(t *testing.T) {
+	cache := NewLFUCacheWithMaxCost(10)
+	cache.PutWithCost("a", "A", 5)
+
+	assert.False(t, cache.PutWithCost("b", "B", -100))
+	assert.False(t, cache.PutWithCost("a", "A2", -1))
+	assert.Equal(t, []interface{}{"a"}, cache.Keys())
+	assert.Equal(t, int64(5), cache.TotalCost())
+}
+
+// TestPutWithCost_CountBounded проверяет, что обновление через PutWithCost в кэше,
+// ограниченном количеством элементов, не вытесняет другие элементы
+func TestPutWithCost_CountBounded(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+
+	assert.True(t, cache.PutWithCost("a", 10, 1))
+	assert.Equal(t, []interface{}{"b", "c", "a"}, cache.Keys())
+	assert.Equal(t, uint64(0), cache.Evictions())
+}
+
+// TestSnapshotLoad проверяет сохранение и восстановление вместе с частотами
+func TestSnapshotLoad(t *testing.T) {
+	src := NewLFUCache(3)
+	src.Put("a", 1)
+	src.Put("b", "two")
+	src.Put("c", 3.0)
+	src.Get("a")
+	src.Get("a") // a.freq=3
+	src.Get("c") // c.freq=2
+
+	var buf bytes.Buffer
+	assert.NoError(t, src.Snapshot(&buf))
+
+	dst := NewLFUCache(3)
+	dst.Put("stale", 0)
+	assert.NoError(t, dst.Load(&buf))
+
+	assert.Equal(t, src.Keys(), dst.Keys(), "Eviction order should survive")
+	assert.Equal(t, 3, dst.items["a"].Value.(*CacheItem).frequency)
+	assert.Equal(t, 2, dst.items["c"].Value.(*CacheItem).frequency)
+	assert.Equal(t, 1, dst.minFreq)
+	_, ok := dst.items["stale"]
+	assert.False(t, ok, "Load should replace previous contents")
+
+	val, _ := dst.Get("b")
+	assert.Equal(t, "two", val)
+
+	dst.Put("d", 4) // b и c с freq=2, c использован раньше и вытесняется
+	_, ok = dst.items["c"]
+	assert.False(t, ok, "c is the LRU entry at the lowest frequency")
+}
+
+// TestLoad_MaxCost проверяет, что загрузка в кэш с меньшим maxCost отбрасывает первых кандидатов
+// на вытеснение, пока суммарная стоимость не уложится в лимит
+func TestLoad_MaxCost(t *testing.T) {
+	src := NewLFUCacheWithMaxCost(30)
+	src.PutWithCost("a", 1, 4)
+	src.PutWithCost("b", 2, 4)
+	src.PutWithCost("c", 3, 4)
+	src.PutWithCost("huge", 4, 9)
+	src.Get("b")
+	src.Get("c")
+	src.Get("c")
+
+	var buf bytes.Buffer
+	assert.NoError(t, src.Snapshot(&buf))
+
+	dst := NewLFUCacheWithMaxCost(8)
+	assert.NoError(t, dst.Load(&buf))
+
+	assert.Equal(t, []interface{}{"b", "c"}, dst.Keys(), "a is dropped first, huge exceeds maxCost on its own")
+	assert.Equal(t, int64(8), dst.TotalCost())
+}
+
+// TestLoad_Errors проверяет, что ошибка разбора не меняет кэш
+func TestLoad_Errors(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("a", 1)
+
+	assert.Error(t, cache.Load(bytes.NewBufferString("garbage")))
+	assert.Equal(t, []interface{}{"a"}, cache.Keys())
+}
+
+// TestLeastFrequent проверяет, что LeastFrequent совпадает с реально вытесняемым элементом
+func TestLeastFrequent(t *testing.T) {
+	cache := NewLFUCache(3)
+	_, _, ok := cache.LeastFrequent()
+	assert.False(t, ok, "Empty cache has no eviction candidate")
+	assert.Equal(t, 0, cache.MinFrequency())
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+	cache.Get("b")
+
+	for _, next := range []string{"d", "e", "f"} {
+		keysBefore := cache.Keys()
+		key, value, ok := cache.LeastFrequent()
+		assert.True(t, ok)
+		assert.Equal(t, value, cache.items[key].Value.(*CacheItem).value)
+		assert.Equal(t, cache.minFreq, cache.MinFrequency())
+		assert.Equal(t, keysBefore, cache.Keys(), "LeastFrequent should not mutate state")
+
+		cache.Put(next, 0)
+		_, stillThere := cache.items[key]
+		assert.False(t, stillThere, "LeastFrequent (%v) should be the evicted key", key)
+	}
+}
+
+// TestPutAllGetAll_Concurrent проверяет под -race, что конкурентные пачки не портят структуры кэша
+func TestPutAllGetAll_Concurrent(t *testing.T) {
+	cache := NewLFUCache(16)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				batch := make(map[interface{}]interface{}, 4)
+				keys := make([]interface{}, 0, 4)
+				for j := 0; j < 4; j++ {
+					key := (g*7 + i + j) % 32
+					batch[key] = i
+					keys = append(keys, key)
+				}
+				cache.PutAll(batch)
+				cache.GetAll(keys)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 16, cache.Len())
+	assert.Len(t, cache.Keys(), cache.Len(), "Frequency lists should stay consistent with the index")
+}
+
+// Тест: PutAll вытесняет наименее часто используемые элементы, GetAll возвращает только попадания и повышает частоту
+func TestPutAllGetAll(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("c")
+
+	cache.PutAll(map[interface{}]interface{}{"d": 4, "e": 5})
+	assert.Equal(t, 3, cache.Len())
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+
+	before := cache.Stats()
+	got := cache.GetAll([]interface{}{"d", "c", "missing"})
+	assert.Equal(t, map[interface{}]interface{}{"d": 4, "c": 3}, got)
+	after := cache.Stats()
+	assert.Equal(t, before.Hits+2, after.Hits)
+	assert.Equal(t, before.Misses+1, after.Misses)
+
+	key, _, _ := cache.LeastFrequent()
+	assert.Equal(t, "e", key, "GetAll should bump frequency of found keys")
+}
+
+// Тест: EvictN вытесняет элементы в порядке политики LFU
+func TestEvictN(t *testing.T) {
+	cache := NewLFUCache(5)
+	assert.Equal(t, 0, cache.EvictN(2), "Empty cache has nothing to evict")
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("c")
+
+	assert.Equal(t, 1, cache.EvictN(1))
+	assert.Equal(t, []interface{}{"c", "a"}, cache.Keys())
+	assert.Equal(t, 2, cache.MinFrequency())
+
+	assert.Equal(t, 2, cache.EvictN(5), "EvictN should stop when the cache is empty")
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 0, cache.MinFrequency())
+	assert.Equal(t, uint64(3), cache.Stats().Evictions)
+}
+
+// Тест: повышение частоты вставляет узлы рядом с текущими, сохраняя сортировку списка частот
+func TestIncrementFrequency_KeepsNodesSorted(t *testing.T) {
+	cache := NewLFUCache(4)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	for i := 0; i < 3; i++ {
+		cache.Get("d")
+	}
+	cache.Get("b") // b переходит в новый узел между 1 и 4
+	cache.Get("c") // c присоединяется к существующему узлу 2
+	cache.Get("c") // c создаёт узел 3 между 2 и 4
+
+	freqs := []int{}
+	for node := cache.freqNodes.Front(); node != nil; node = node.Next() {
+		freqs = append(freqs, node.Value.(*FrequencyNode).freq)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4}, freqs)
+	assert.Equal(t, []interface{}{"a", "b", "c", "d"}, cache.Keys())
+	assert.Equal(t, 1, cache.MinFrequency())
+
+	cache.Get("a") // узел 1 исчезает, a встаёт за b в узле 2
+	assert.Equal(t, []interface{}{"b", "a", "c", "d"}, cache.Keys())
+	assert.Equal(t, 2, cache.MinFrequency())
+}
+
+// BenchmarkGet_ManyFrequencies измеряет Get, когда частоты элементов идут через одну и каждое
+// обращение создаёт новый узел частоты. Раньше место такого узла искалось перебором списка
+func BenchmarkGet_ManyFrequencies(b *testing.B) {
+	const n = 1000
+	cache := NewLFUCache(n)
+	for i := 0; i < n; i++ {
+		cache.Put(i, i)
+		for j := 0; j < 2*i; j++ {
+			cache.Get(i)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(n - 1 - i%n)
+	}
+}
+
+// Тест: единственный элемент узла переносится на следующую частоту переименованием узла
+func TestIncrementFrequency_RenamesSingleNode(t *testing.T) {
+	cache := NewLFUCache(2)
+	cache.Put("a", 1)
+	cache.Get("a")
+	cache.Get("a")
+
+	assert.Equal(t, 1, cache.freqNodes.Len())
+	assert.Equal(t, 3, cache.MinFrequency())
+	assert.Equal(t, []interface{}{"a"}, cache.KeysAtFrequency(3))
+	assert.Nil(t, cache.getFrequencyList(1))
+	assert.Nil(t, cache.getFrequencyList(2))
+
+	cache.Put("b", 2)
+	cache.Get("b")
+	cache.Get("b") // b догоняет a и попадает в уже существующий узел 3
+	assert.Equal(t, []interface{}{"a", "b"}, cache.KeysAtFrequency(3))
+	assert.Equal(t, 1, cache.freqNodes.Len())
+	assert.Equal(t, 3, cache.MinFrequency())
+}
+
+// Тест: Put существующего ключа, единственного с минимальной частотой, сдвигает minFreq
+func TestPut_UpdateOnlyMinFreqKey(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("b")
+	cache.Get("b")
+	assert.Equal(t, 1, cache.MinFrequency())
+
+	cache.Put("a", 10) // a: 1 -> 2, узел 1 исчезает
+	assert.Equal(t, 2, cache.MinFrequency())
+	assert.Equal(t, []interface{}{"a"}, cache.KeysAtFrequency(2))
+
+	cache.Put("a", 11) // a: 2 -> 3, присоединяется к b
+	assert.Equal(t, 3, cache.MinFrequency())
+	assert.Equal(t, []interface{}{"b", "a"}, cache.KeysAtFrequency(3))
+
+	value, _ := cache.Get("a")
+	assert.Equal(t, 11, value)
+	assert.Equal(t, 3, cache.MinFrequency(), "b is still at frequency 3")
+}
+
+// BenchmarkLFUGetHot измеряет повторные обращения к одному горячему ключу
+func BenchmarkLFUGetHot(b *testing.B) {
+	cache := NewLFUCache(100)
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(42)
+	}
+}
+
+// Тест: String группирует элементы по частотам и не меняет их
+func TestString(t *testing.T) {
+	cache := NewLFUCache(3)
+	assert.Equal(t, "{}", cache.String())
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("c")
+	cache.Get("c")
+
+	assert.Equal(t, "{1: [a=1, b=2], 3: [c=3]}", cache.String())
+	assert.Equal(t, "{1: [a=1, b=2], 3: [c=3]}", cache.String(), "String should not change frequencies")
+}
+
+// Тест: Values возвращает значения в порядке Keys и не меняет частоты
+func TestValues(t *testing.T) {
+	cache := NewLFUCache(3)
+	assert.Empty(t, cache.Values())
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("b")
+
+	assert.Equal(t, []interface{}{3, 2, 1}, cache.Values())
+	assert.Equal(t, []interface{}{"c", "b", "a"}, cache.Keys())
+	assert.Equal(t, 1, cache.MinFrequency(), "Values should not change frequencies")
+}
+
+// Тест: ToMap и NewLFUFromMap переносят элементы без изменений
+func TestToMapRoundTrip(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("c")
+
+	m := cache.ToMap()
+	assert.Equal(t, map[interface{}]interface{}{"a": 1, "b": 2, "c": 3}, m)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, cache.Keys(), "ToMap should not change frequencies")
+	assert.Equal(t, uint64(1), cache.Stats().Hits)
+
+	restored := NewLFUFromMap(3, m)
+	assert.Equal(t, m, restored.ToMap())
+	assert.Equal(t, 1, restored.MinFrequency())
+
+	overflow := NewLFUFromMap(2, m)
+	assert.Equal(t, 2, overflow.Len(), "Overflow should evict down to capacity")
+}
+
+// Тест: GetMultiple разделяет попадания и промахи, сохраняя порядок промахов
+func TestGetMultiple(t *testing.T) {
+	cache := NewLFUCache(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	values, misses := cache.GetMultiple([]interface{}{"x", "b", "y", "a"})
+	assert.Equal(t, map[interface{}]interface{}{"a": 1, "b": 2}, values)
+	assert.Equal(t, []interface{}{"x", "y"}, misses)
+	assert.Equal(t, []interface{}{"b", "a"}, cache.KeysAtFrequency(2), "Hits should be promoted in request order")
+	assert.Equal(t, uint64(2), cache.Stats().Misses)
+}
+
+// Тест: GetMultiple выполняется под одной блокировкой и вызывает хуки после её снятия
+func TestGetMultiple_Concurrent(t *testing.T) {
+	var cache *LFUCache
+	cache = NewLFUCache(8, WithOnMiss(func(key interface{}) {
+		cache.Put(key, key) // хук может обращаться к кэшу
+	}))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				cache.GetMultiple([]interface{}{(g + i) % 16, (g + i + 1) % 16})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8, cache.Len())
+	assert.Len(t, cache.Keys(), cache.Len())
+}
+
+// Тест: Metadata отражает Get и Put, но не операции без обращения
+func TestMetadata(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := NewLFUCache(2, WithClock(func() time.Time { return now }))
+
+	_, _, ok := cache.Metadata("a")
+	assert.False(t, ok)
+
+	cache.Put("a", 1)
+	lastAccess, count, ok := cache.Metadata("a")
+	assert.True(t, ok)
+	assert.Equal(t, now, lastAccess)
+	assert.Equal(t, 1, count)
+
+	now = now.Add(time.Second)
+	cache.Get("a")
+	lastAccess, count, _ = cache.Metadata("a")
+	assert.Equal(t, now, lastAccess)
+	assert.Equal(t, 2, count)
+
+	now = now.Add(time.Second)
+	cache.Keys()
+	cache.LeastFrequent()
+	lastAccess, count, _ = cache.Metadata("a")
+	assert.Equal(t, now.Add(-time.Second), lastAccess, "Inspection should not update the access time")
+	assert.Equal(t, 2, count)
+
+	cache.Put("a", 10)
+	lastAccess, count, _ = cache.Metadata("a")
+	assert.Equal(t, now, lastAccess)
+	assert.Equal(t, 3, count)
+}
+
+// Тест: вытеснение и явное удаление порождают события с нужной причиной
+func TestEvictionEvents(t *testing.T) {
+	c := NewLFUCache(2)
+	events := c.EvictionEvents()
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Get("b")
+	c.Put("c", 3)
+	assert.Equal(t, cache.EvictedEntry{Key: "a", Value: 1, Reason: cache.EvictedCapacity}, <-events)
+
+	c.Remove("b")
+	assert.Equal(t, cache.EvictedEntry{Key: "b", Value: 2, Reason: cache.EvictedManual}, <-events)
+
+	c.EvictN(1)
+	assert.Equal(t, cache.EvictedEntry{Key: "c", Value: 3, Reason: cache.EvictedManual}, <-events)
+
+	c.StopEvictionEvents()
+	_, open := <-events
+	assert.False(t, open, "Stopped channel should be closed")
+}
+
+// Тест: вытеснение по стоимости тоже порождает события, а заполненный буфер не блокирует кэш
+func TestEvictionEvents_CostAndFullBuffer(t *testing.T) {
+	c := NewLFUCacheWithMaxCost(3)
+	events := c.EvictionEvents()
+
+	c.PutWithCost("a", 1, 2)
+	c.PutWithCost("b", 2, 2)
+	assert.Equal(t, cache.EvictedEntry{Key: "a", Value: 1, Reason: cache.EvictedCapacity}, <-events)
+
+	for i := 0; i < cache.EventBufferSize*2; i++ {
+		c.PutWithCost(i, i, 3)
+	}
+	assert.Equal(t, cache.EventBufferSize, len(events))
+}
+
+// Тест: при равной частоте TieBreakLRU вытесняет дольше всех не использованный элемент,
+// а TieBreakFIFO - раньше всех добавленный
+func TestTieBreak(t *testing.T) {
+	fill := func(c *LFUCache) {
+		c.Put("a", 1)
+		c.Put("b", 2)
+		c.Put("c", 3)
+		c.Get("b")
+		c.Get("a")
+		c.Get("c")
+	}
+
+	lru := NewLFUCache(3)
+	fill(lru)
+	assert.Equal(t, []interface{}{"b", "a", "c"}, lru.KeysAtFrequency(2))
+	lru.Put("d", 4)
+	_, ok := lru.Get("b")
+	assert.False(t, ok, "LRU tie-break evicts the least recently promoted key")
+
+	fifo := NewLFUCache(3, WithTieBreak(TieBreakFIFO))
+	fill(fifo)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, fifo.KeysAtFrequency(2), "Reads should not reorder keys")
+	fifo.Put("d", 4)
+	_, ok = fifo.Get("a")
+	assert.False(t, ok, "FIFO tie-break evicts the earliest inserted key")
+	_, ok = fifo.Get("b")
+	assert.True(t, ok)
+}
+
+// Тест: при TieBreakFIFO порядок добавления сохраняется при слиянии списков частот и после Load
+func TestTieBreakFIFO_DecayAndLoad(t *testing.T) {
+	c := NewLFUCache(3, WithTieBreak(TieBreakFIFO))
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	for i := 0; i < 3; i++ {
+		c.Get("c")
+	}
+	c.Get("a")
+
+	c.Decay(0.1) // все частоты становятся 1
+	assert.Equal(t, []interface{}{"a", "b", "c"}, c.KeysAtFrequency(1))
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.Snapshot(&buf))
+	restored := NewLFUCache(3, WithTieBreak(TieBreakFIFO))
+	assert.NoError(t, restored.Load(&buf))
+	restored.Get("b")
+	restored.Get("a")
+	assert.Equal(t, []interface{}{"a", "b"}, restored.KeysAtFrequency(2))
+}
+
+// Тест: в замороженном кэше записи игнорируются, а чтения не меняют частоты
+func TestFreeze(t *testing.T) {
+	c := NewLFUCache(2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Freeze()
+	assert.True(t, c.Frozen())
+
+	assert.False(t, c.Add("c", 3))
+	c.Put("a", 10)
+	assert.False(t, c.PutWithCost("d", 4, 1))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.EvictN(1))
+
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value, "Frozen Put should not update values")
+	c.Get("a")
+	assert.Equal(t, []interface{}{"a", "b"}, c.KeysAtFrequency(1), "Frozen Get should not change frequencies")
+	assert.Equal(t, uint64(2), c.Stats().Hits)
+
+	c.Unfreeze()
+	c.Get("a")
+	c.Put("c", 3)
+	assert.Equal(t, []interface{}{"c", "a"}, c.Keys())
+}
+
+// Тест: изменения копии не затрагивают оригинал и наоборот
+func TestClone(t *testing.T) {
+	c := NewLFUCache(3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("c")
+
+	clone := c.Clone()
+	assert.Equal(t, c.String(), clone.String())
+
+	clone.Get("a")
+	clone.Get("a")
+	clone.Put("d", 4)
+	assert.Equal(t, "{1: [d=4], 2: [c=3], 3: [a=1]}", clone.String())
+	assert.Equal(t, "{1: [a=1, b=2], 2: [c=3]}", c.String(), "Source frequencies should not change")
+
+	c.Remove("c")
+	c.Put("e", 5)
+	assert.Equal(t, "{1: [d=4], 2: [c=3], 3: [a=1]}", clone.String(), "Clone should not see source changes")
+	assert.Equal(t, 1, c.MinFrequency())
+	assert.Equal(t, 1, clone.MinFrequency())
+	assert.Equal(t, Stats{Hits: 1}, c.Stats())
+	assert.Equal(t, Stats{Hits: 3, Evictions: 1}, clone.Stats(), "Clone starts from the source statistics")
+}
+
+// Тест: RemovePrefix удаляет только строковые ключи с префиксом и поддерживает minFreq
+func TestRemovePrefix(t *testing.T) {
+	c := NewLFUCache(10)
+	c.Put("user:1:profile", "p1")
+	c.Put("user:1:settings", "s1")
+	c.Put("user:2:profile", "p2")
+	c.Put(1, "int key")
+	c.Get("user:2:profile")
+	c.Get(1)
+
+	assert.Equal(t, 2, c.RemovePrefix("user:1:"))
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, 2, c.MinFrequency(), "minFreq should advance past the removed group")
+
+	assert.Equal(t, 0, c.RemovePrefix("order:"))
+	assert.Equal(t, 1, c.RemovePrefix("user:"))
+	assert.Equal(t, []interface{}{1}, c.Keys())
+}
+
+// BenchmarkLFUFillLarge измеряет заполнение большого кэша с нуля
+func BenchmarkLFUFillLarge(b *testing.B) {
+	const n = 100000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cache := NewLFUCache(n)
+		for j := 0; j < n; j++ {
+			cache.Put(j, j)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package lfu
+
+// typedCacheItem - элемент типизированного кэша, одновременно узел списка своей частоты
+type typedCacheItem[K comparable, V any] struct {
+	key        K
+	value      V
+	frequency  int
+	node       *typedFrequencyNode[K, V] // узел частоты, в котором находится элемент
+	prev, next *typedCacheItem[K, V]
+}
+
+// typedFrequencyNode - узел частоты со списком элементов в порядке LRU (head вытесняется первым)
+type typedFrequencyNode[K comparable, V any] struct {
+	freq       int
+	head, tail *typedCacheItem[K, V]
+	prev, next *typedFrequencyNode[K, V]
+}
+
+// TypedLFUCache - типобезопасный LFU кэш на дженериках, не требующий приведения типов
+type TypedLFUCache[K comparable, V any] struct {
+	capacity int
+	minFreq  int
+
+	items     map[K]*typedCacheItem[K, V]
+	freqNodes *typedFrequencyNode[K, V] // узел с минимальной частотой, узлы отсортированы по возрастанию
+}
+
+// NewTypedLFUCache создает новый типизированный LFU кэш
+func NewTypedLFUCache[K comparable, V any](capacity int) *TypedLFUCache[K, V] {
+	if capacity <= 0 {
+		panic("capacity must be positive")
+	}
+	return &TypedLFUCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*typedCacheItem[K, V]),
+	}
+}
+
+// Get получает значение по ключу и увеличивает его частоту
+func (c *TypedLFUCache[K, V]) Get(key K) (value V, ok bool) {
+	item, exists := c.items[key]
+	if !exists {
+		return value, false
+	}
+	c.incrementFrequency(item)
+	return item.value, true
+}
+
+// Put добавляет или обновляет значение
+func (c *TypedLFUCache[K, V]) Put(key K, value V) {
+	if item, exists := c.items[key]; exists {
+		item.value = value
+		c.incrementFrequency(item)
+		return
+	}
+
+	if len(c.items) >= c.capacity {
+		c.evict()
+	}
+
+	// Новый элемент всегда попадает в узел с частотой 1, который может быть только первым
+	node := c.freqNodes
+	if node == nil || node.freq != 1 {
+		node = c.insertFrequencyNodeAfter(nil, 1)
+	}
+	item := &typedCacheItem[K, V]{key: key, value: value, frequency: 1}
+	node.pushBack(item)
+	c.items[key] = item
+	c.minFreq = 1
+}
+
+// Len возвращает текущее количество элементов в кэше
+func (c *TypedLFUCache[K, V]) Len() int {
+	return len(c.items)
+}
+
+// incrementFrequency переносит элемент в соседний узел со следующей частотой за O(1)
+func (c *TypedLFUCache[K, V]) incrementFrequency(item *typedCacheItem[K, V]) {
+	node := item.node
+	newFreq := item.frequency + 1
+
+	target := node.next
+	if target == nil || target.freq != newFreq {
+		target = c.insertFrequencyNodeAfter(node, newFreq)
+	}
+
+	node.remove(item)
+	if node.head == nil {
+		c.removeFrequencyNode(node)
+		if node.freq == c.minFreq {
+			c.minFreq = newFreq
+		}
+	}
+
+	item.frequency = newFreq
+	target.pushBack(item)
+}
+
+// evict удаляет наименее часто используемый элемент
+func (c *TypedLFUCache[K, V]) evict() {
+	node := c.freqNodes
+	if node == nil {
+		return
+	}
+
+	item := node.head
+	node.remove(item)
+	delete(c.items, item.key)
+	if node.head == nil {
+		c.removeFrequencyNode(node)
+	}
+}
+
+// insertFrequencyNodeAfter создает узел частоты сразу после prev (или в начале списка, если prev == nil)
+func (c *TypedLFUCache[K, V]) insertFrequencyNodeAfter(prev *typedFrequencyNode[K, V], freq int) *typedFrequencyNode[K, V] {
+	node := &typedFrequencyNode[K, V]{freq: freq, prev: prev}
+	if prev == nil {
+		node.next = c.freqNodes
+		c.freqNodes = node
+	} else {
+		node.next = prev.next
+		prev.next = node
+	}
+	if node.next != nil {
+		node.next.prev = node
+	}
+	return node
+}
+
+// removeFrequencyNode исключает пустой узел частоты из списка
+func (c *TypedLFUCache[K, V]) removeFrequencyNode(node *typedFrequencyNode[K, V]) {
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		c.freqNodes = node.next
+	}
+	if node.next != nil {
+		node.next.prev = node.prev
+	}
+	node.prev, node.next = nil, nil
+}
+
+// pushBack добавляет элемент в конец списка узла (самый недавно использованный)
+func (n *typedFrequencyNode[K, V]) pushBack(item *typedCacheItem[K, V]) {
+	item.node = n
+	item.prev = n.tail
+	item.next = nil
+	if n.tail != nil {
+		n.tail.next = item
+	} else {
+		n.head = item
+	}
+	n.tail = item
+}
+
+// remove исключает элемент из списка узла
+func (n *typedFrequencyNode[K, V]) remove(item *typedCacheItem[K, V]) {
+	if item.prev != nil {
+		item.prev.next = item.next
+	} else {
+		n.head = item.next
+	}
+	if item.next != nil {
+		item.next.prev = item.prev
+	} else {
+		n.tail = item.prev
+	}
+	item.prev, item.next, item.node = nil, nil, nil
+}
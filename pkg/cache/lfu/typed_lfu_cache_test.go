@@ -0,0 +1,78 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type payload struct {
+	data string
+}
+
+// TestTypedLFU_PutGet проверяет добавление и получение указателей по целым ключам
+func TestTypedLFU_PutGet(t *testing.T) {
+	cache := NewTypedLFUCache[int, *payload](2)
+	p1 := &payload{data: "one"}
+	cache.Put(1, p1)
+
+	val, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.Same(t, p1, val, "Pointer value should be returned as is")
+
+	val, ok = cache.Get(42)
+	assert.False(t, ok)
+	assert.Nil(t, val, "Miss should return the zero value")
+}
+
+// TestTypedLFU_EvictionOrder проверяет вытеснение по частоте и LRU при равной частоте
+func TestTypedLFU_EvictionOrder(t *testing.T) {
+	cache := NewTypedLFUCache[int, *payload](3)
+	cache.Put(1, &payload{"a"})
+	cache.Put(2, &payload{"b"})
+	cache.Put(3, &payload{"c"})
+	cache.Get(1)
+	cache.Get(1) // 1.freq=3
+	cache.Get(3) // 3.freq=2, 2.freq=1
+
+	cache.Put(4, &payload{"d"}) // вытесняется 2
+	_, ok := cache.Get(2)
+	assert.False(t, ok, "Least frequent key should be evicted")
+
+	cache.Put(5, &payload{"e"}) // 4 и 5 с freq=1, вытесняется 4 (LRU)
+	_, ok = cache.Get(4)
+	assert.False(t, ok, "LRU key within the lowest frequency should be evicted")
+	assert.Equal(t, 3, cache.Len())
+
+	for _, key := range []int{1, 3, 5} {
+		_, ok := cache.Get(key)
+		assert.True(t, ok, "key %d should remain", key)
+	}
+}
+
+// TestTypedLFU_UpdateAndMinFreq проверяет обновление значения и сопровождение minFreq
+func TestTypedLFU_UpdateAndMinFreq(t *testing.T) {
+	cache := NewTypedLFUCache[int, *payload](2)
+	cache.Put(1, &payload{"a"})
+	assert.Equal(t, 1, cache.minFreq)
+
+	updated := &payload{"a2"}
+	cache.Put(1, updated)
+	assert.Equal(t, 2, cache.minFreq, "minFreq should follow the only key")
+
+	val, _ := cache.Get(1)
+	assert.Same(t, updated, val)
+	assert.Equal(t, 3, cache.items[1].frequency)
+
+	cache.Put(2, &payload{"b"})
+	assert.Equal(t, 1, cache.minFreq)
+	assert.Equal(t, 1, cache.freqNodes.freq, "Frequency nodes should stay sorted")
+	assert.Equal(t, 3, cache.freqNodes.next.freq)
+}
+
+// TestTypedLFU_InvalidCapacity проверяет панику при некорректной ёмкости
+func TestTypedLFU_InvalidCapacity(t *testing.T) {
+	assert.Panics(t, func() {
+		NewTypedLFUCache[string, int](0)
+	})
+}
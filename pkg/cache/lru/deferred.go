@@ -0,0 +1,103 @@
+package lru
+
+import "sync/atomic"
+
+// promotionBuffer - буфер ключей, чьё повышение приоритета отложено.
+// Слоты заполняются под блокировкой чтения: каждый Get атомарно получает свой номер слота,
+// поэтому запись в буфер не требует эксклюзивной блокировки. Разбор буфера выполняется
+// под блокировкой записи, когда ни один Get не пишет в слоты
+type promotionBuffer struct {
+	keys []interface{}
+	n    uint64 // число занятых слотов, может превышать len(keys), если буфер переполнен
+}
+
+func newPromotionBuffer(size int) *promotionBuffer {
+	return &promotionBuffer{keys: make([]interface{}, size)}
+}
+
+// record запоминает обращение к ключу и сообщает, что этот вызов заполнил последний слот.
+// Обращения сверх ёмкости буфера до его разбора теряются
+func (b *promotionBuffer) record(key interface{}) (full bool) {
+	i := atomic.AddUint64(&b.n, 1) - 1
+	if i >= uint64(len(b.keys)) {
+		return false
+	}
+	b.keys[i] = key
+	return i == uint64(len(b.keys))-1
+}
+
+// WithDeferredPromotion включает отложенное повышение приоритета для нагрузки с преобладанием чтения.
+// Get берёт только блокировку чтения и записывает ключ в буфер на bufferSize обращений, а элементы
+// переносятся в начало очереди при следующей операции под блокировкой записи или при заполнении буфера.
+// Порядок вытеснения поэтому приблизителен: обращения, не попавшие в переполненный буфер, не учитываются
+func WithDeferredPromotion(bufferSize int) Option {
+	if bufferSize <= 0 {
+		panic("promotion buffer size must be positive")
+	}
+	return func(L *LRU) {
+		L.promotions = newPromotionBuffer(bufferSize)
+	}
+}
+
+// lock захватывает блокировку записи и применяет отложенные повышения приоритета,
+// чтобы любая операция видела актуальный порядок
+func (L *LRU) lock() {
+	L.mu.Lock()
+	L.flushPromotions()
+}
+
+// getDeferred - Get под блокировкой чтения: повышение приоритета только записывается в буфер.
+// Тот, кто заполнил буфер, сразу применяет накопленные повышения
+func (L *LRU) getDeferred(key interface{}) (value interface{}, ok bool) {
+	L.mu.RLock()
+	element, exists := L.items[L.mapKey(key)]
+	if !exists {
+		L.mu.RUnlock()
+		atomic.AddUint64(&L.misses, 1)
+		if L.onMiss != nil {
+			L.onMiss(key)
+		}
+		return nil, false
+	}
+	item := element.Value.(*Item)
+	key, value, onAccess := item.Key, item.Value, item.onAccess
+	full := !L.frozen && L.promotions.record(key)
+	L.mu.RUnlock()
+	atomic.AddUint64(&L.hits, 1)
+
+	if full {
+		L.lock()
+		L.mu.Unlock()
+	}
+	if L.onHit != nil {
+		L.onHit(key)
+	}
+	if onAccess != nil {
+		onAccess(key, value)
+	}
+	return value, true
+}
+
+// flushPromotions переносит в начало очереди элементы из буфера в порядке обращений.
+// Вызывается под блокировкой записи; ключи, удалённые после обращения, пропускаются
+func (L *LRU) flushPromotions() {
+	b := L.promotions
+	if b == nil {
+		return
+	}
+	n := atomic.LoadUint64(&b.n)
+	if n == 0 {
+		return
+	}
+	if n > uint64(len(b.keys)) {
+		n = uint64(len(b.keys))
+	}
+	for i := uint64(0); i < n; i++ {
+		if element, ok := L.items[L.mapKey(b.keys[i])]; ok && !L.frozen {
+			L.queue.MoveToFront(element)
+			L.touch(element.Value.(*Item))
+		}
+		b.keys[i] = nil
+	}
+	atomic.StoreUint64(&b.n, 0)
+}
@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест: отложенные повышения применяются при следующей записи
+func TestDeferredPromotion_AppliedOnWrite(t *testing.T) {
+	lru := NewLRU(3, WithDeferredPromotion(16))
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	val, ok := lru.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	assert.Equal(t, "c", lru.queue.Front().Value.(*Item).Key, "Promotion should be deferred")
+
+	lru.Add("d", 4)
+	_, ok = lru.Peek("a")
+	assert.True(t, ok, "Deferred promotion should protect a from eviction")
+	_, ok = lru.Peek("b")
+	assert.False(t, ok, "b should be evicted instead")
+
+	hits, misses := lru.Stats()
+	assert.Equal(t, uint64(1), hits)
+	assert.Equal(t, uint64(0), misses)
+}
+
+// Тест: заполнение буфера сразу применяет накопленные повышения в порядке обращений
+func TestDeferredPromotion_FlushWhenFull(t *testing.T) {
+	lru := NewLRU(3, WithDeferredPromotion(2))
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	lru.Get("b")
+	lru.Get("a") // буфер заполнен
+	assert.Equal(t, uint64(0), lru.promotions.n, "Full buffer should be flushed")
+	assert.Equal(t, "a", lru.queue.Front().Value.(*Item).Key)
+	assert.Equal(t, "b", lru.queue.Front().Next().Value.(*Item).Key)
+
+	_, ok := lru.Get("missing")
+	assert.False(t, ok)
+	lru.Get("c")
+	lru.Remove("c")
+	lru.Add("d", 4) // c уже удалён, его отложенное повышение пропускается
+	assert.Equal(t, []interface{}{"d", "a", "b"}, lru.Keys())
+}
+
+// Тест: конкурентные Get и Add в режиме отложенного повышения не нарушают структуру кеша
+func TestDeferredPromotion_Concurrent(t *testing.T) {
+	lru := NewLRU(64, WithDeferredPromotion(8))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if i%10 == 0 {
+					lru.Add((g*i)%128, i)
+				} else {
+					lru.Get(i % 128)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, lru.Len(), 64)
+	assert.Equal(t, lru.Len(), len(lru.Keys()))
+}
+
+func benchmarkParallelGet(b *testing.B, lru *LRU) {
+	for i := 0; i < 1024; i++ {
+		lru.Add(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			lru.Get(i % 1024)
+			i++
+		}
+	})
+}
+
+// BenchmarkGet_Parallel измеряет конкурентные чтения с немедленным повышением приоритета
+func BenchmarkGet_Parallel(b *testing.B) {
+	benchmarkParallelGet(b, NewLRU(1024))
+}
+
+// BenchmarkGet_ParallelDeferred измеряет те же чтения в режиме WithDeferredPromotion
+func BenchmarkGet_ParallelDeferred(b *testing.B) {
+	benchmarkParallelGet(b, NewLRU(1024, WithDeferredPromotion(64)))
+}
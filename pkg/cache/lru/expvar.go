@@ -0,0 +1,43 @@
+package lru
+
+import (
+	"expvar"
+	"fmt"
+	"sync/atomic"
+)
+
+// Metrics - метрики кэша, публикуемые через expvar
+type Metrics struct {
+	Size      int    `json:"size"`
+	Capacity  int    `json:"capacity"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// PublishExpvar регистрирует в expvar переменную name, которая при чтении отдаёт Metrics в виде JSON.
+// Если переменная с таким именем уже опубликована, возвращается ошибка
+func (L *LRU) PublishExpvar(name string) error {
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %q is already published", name)
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return L.metrics()
+	}))
+	return nil
+}
+
+// metrics собирает текущие значения метрик
+func (L *LRU) metrics() Metrics {
+	L.lock()
+	size, capacity := len(L.items), L.capacity
+	L.mu.Unlock()
+
+	return Metrics{
+		Size:      size,
+		Capacity:  capacity,
+		Hits:      atomic.LoadUint64(&L.hits),
+		Misses:    atomic.LoadUint64(&L.misses),
+		Evictions: atomic.LoadUint64(&L.evictions),
+	}
+}
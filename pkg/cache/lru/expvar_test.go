@@ -0,0 +1,26 @@
+package lru
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест: опубликованная переменная отдаёт актуальные метрики, повторная публикация возвращает ошибку
+func TestLRU_PublishExpvar(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	assert.NoError(t, lru.PublishExpvar("lru_test_metrics"))
+	assert.Error(t, lru.PublishExpvar("lru_test_metrics"), "Duplicate name should return an error")
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3) // вытесняет a
+	lru.Get("b")
+	lru.Get("a")
+
+	var metrics Metrics
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("lru_test_metrics").String()), &metrics))
+	assert.Equal(t, Metrics{Size: 2, Capacity: 2, Hits: 1, Misses: 1, Evictions: 1}, metrics)
+}
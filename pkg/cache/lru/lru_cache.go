@@ -3,78 +3,1200 @@ package lru
 import (
 	"LRU_cache/pkg/cache"
 	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Item struct {
-	Key   interface{}
-	Value interface{}
+	Key      interface{}
+	Value    interface{}
+	onAccess func(key, value interface{})
+	updated  time.Time // время добавления или последнего обновления значения
+	cost     int64
+
+	accessed time.Time // время последнего Get или Add этого ключа
+	accesses int       // число Get и Add этого ключа
+
+	tags []string // теги, заданные через AddWithTags
 }
 
+// LRU безопасен для конкурентного использования: все операции выполняются под мьютексом
 type LRU struct {
-	capacity int
-	items    map[interface{}]*list.Element
-	queue    *list.List
+	mu             sync.RWMutex
+	capacity       int
+	lowWatermark   int // размер кеша после пакетного вытеснения (0 - вытеснять по одному), см. NewLRUCacheWatermark
+	items          map[interface{}]*list.Element
+	queue          *list.List
+	defaultFactory func(key interface{}) interface{}
+	now            func() time.Time
+
+	// Ограничение по суммарной стоимости вместо количества элементов (0 - не используется)
+	maxCost   int64
+	totalCost int64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	inflight map[interface{}]*call // загрузки GetOrCompute, выполняющиеся прямо сейчас
+
+	events chan cache.EvictedEntry // канал событий об удалении, nil - никто не подписан
+	closed bool                    // кеш закрыт через Close, новые подписки на события не создаются
+
+	frozen bool // режим только для чтения, см. Freeze
+
+	tags map[string]map[interface{}]struct{} // тег -> ключи с этим тегом
+
+	promotions *promotionBuffer // отложенные повышения приоритета, nil - Get повышает сразу
+
+	keyFunc func(key interface{}) string // строит строковый ключ внутренних map, см. WithKeyFunc
+
+	onHit  func(key interface{}) // вызывается на попадание Get, см. WithOnHit
+	onMiss func(key interface{}) // вызывается на промах Get, см. WithOnMiss
+}
+
+// call - выполняющаяся загрузка значения, результат которой ждут остальные вызывающие
+type call struct {
+	wg         sync.WaitGroup
+	value      interface{}
+	err        error
+	panicValue interface{} // значение panic загрузчика, которое получают и ожидающие вызывающие
+}
+
+// Option настраивает LRU кэш при создании
+type Option func(*LRU)
+
+// WithClock подменяет источник текущего времени (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(L *LRU) {
+		L.now = now
+	}
+}
+
+// WithKeyFunc задаёт функцию, строящую по ключу стабильную строку, на которой строятся внутренние map.
+// Так в кеше можно хранить несравнимые ключи, например []byte: равные по keyFunc ключи считаются одним.
+// Исходный ключ сохраняется в Item и передаётся в колбэки, Keys и события. ToMap и NewLRUFromMap
+// по-прежнему требуют сравнимых ключей
+func WithKeyFunc(keyFunc func(key interface{}) string) Option {
+	return func(L *LRU) {
+		L.keyFunc = keyFunc
+	}
+}
+
+// mapKey возвращает ключ внутренних map: результат keyFunc, если она задана, иначе сам ключ
+func (L *LRU) mapKey(key interface{}) interface{} {
+	if L.keyFunc == nil {
+		return key
+	}
+	return L.keyFunc(key)
+}
+
+// WithOnHit задаёт хук, вызываемый из Get при попадании, например для трассировки обращений.
+// Хук вызывается после снятия блокировки и до колбэка доступа элемента, поэтому может обращаться к кешу
+func WithOnHit(onHit func(key interface{})) Option {
+	return func(L *LRU) {
+		L.onHit = onHit
+	}
+}
+
+// WithOnMiss задаёт хук, вызываемый из Get при промахе. Как и WithOnHit, вызывается после снятия блокировки
+func WithOnMiss(onMiss func(key interface{})) Option {
+	return func(L *LRU) {
+		L.onMiss = onMiss
+	}
+}
+
+// WithDefaultFactory задаёт функцию, строящую значение по умолчанию для GetOrDefaultFactory
+func WithDefaultFactory(fn func(key interface{}) interface{}) Option {
+	return func(L *LRU) {
+		L.defaultFactory = fn
+	}
 }
 
 func (L *LRU) Add(key, value interface{}) bool {
-	if element, exists := L.items[key]; exists == true {
+	L.lock()
+	defer L.mu.Unlock()
+	return L.add(key, value)
+}
+
+func (L *LRU) add(key, value interface{}) bool {
+	if L.frozen {
+		return false
+	}
+	if element, exists := L.items[L.mapKey(key)]; exists == true {
+		item := element.Value.(*Item)
+		item.Value = value
+		item.updated = L.now()
+		L.touch(item)
 		L.queue.MoveToFront(element)
 		return false
 	}
+	return L.insert(key, value, 1)
+}
+
+// AddEvict работает как Add и возвращает элемент, вытесненный, чтобы освободить место.
+// Добавление и определение вытесненного элемента выполняются под одной блокировкой.
+// При ограничении по суммарной стоимости может вытесняться несколько элементов, тогда возвращается первый из них
+func (L *LRU) AddEvict(key, value interface{}) (evictedKey, evictedValue interface{}, evicted bool) {
+	L.lock()
+	defer L.mu.Unlock()
+
+	// Вытеснение всегда идёт с конца очереди, поэтому первым уходит последний элемент, кроме самого key
+	victim := L.queue.Back()
+	if victim != nil && L.mapKey(victim.Value.(*Item).Key) == L.mapKey(key) {
+		victim = victim.Prev()
+	}
+	before := atomic.LoadUint64(&L.evictions)
+	L.add(key, value)
+
+	if victim == nil || atomic.LoadUint64(&L.evictions) == before {
+		return nil, nil, false
+	}
+	item := victim.Value.(*Item)
+	return item.Key, item.Value, true
+}
+
+// AddWithCost работает как Add, но учитывает стоимость элемента при ограничении по суммарной стоимости.
+// В отличие от Add, результат означает, что запись принята: true и для нового ключа, и для обновления существующего.
+// Элемент, стоимость которого превышает maxCost или отрицательна, отклоняется целиком: возвращается false, кеш не меняется.
+// При переполнении вытесняется столько наименее приоритетных элементов, сколько нужно
+func (L *LRU) AddWithCost(key, value interface{}, cost int64) bool {
+	L.lock()
+	defer L.mu.Unlock()
+
+	if L.frozen || cost < 0 || L.maxCost > 0 && cost > L.maxCost {
+		return false
+	}
 
-	if L.capacity == 0 {
+	if element, exists := L.items[L.mapKey(key)]; exists {
+		item := element.Value.(*Item)
+		item.Value = value
+		item.updated = L.now()
+		L.touch(item)
+		L.totalCost += cost - item.cost
+		item.cost = cost
+		L.queue.MoveToFront(element)
+		L.evictOverCost()
 		return true
 	}
+	return L.insert(key, value, cost)
+}
 
-	if L.queue.Len() == L.capacity {
-		L.removeLastElement()
+// insert добавляет новый элемент в начало очереди, вытесняя элементы по количеству или по стоимости
+func (L *LRU) insert(key, value interface{}, cost int64) bool {
+	if L.maxCost > 0 {
+		if cost > L.maxCost {
+			return false
+		}
+	} else {
+		if L.capacity == 0 {
+			return true
+		}
+		if L.queue.Len() == L.capacity {
+			L.removeLastElement(cache.EvictedCapacity)
+			for L.lowWatermark > 0 && L.queue.Len() >= L.lowWatermark {
+				L.removeLastElement(cache.EvictedCapacity)
+			}
+		}
 	}
 
 	item := &Item{
-		Key:   key,
-		Value: value,
+		Key:     key,
+		Value:   value,
+		updated: L.now(),
+		cost:    cost,
 	}
+	L.touch(item)
 
 	element := L.queue.PushFront(item)
-	L.items[item.Key] = element
+	L.items[L.mapKey(item.Key)] = element
+	L.totalCost += cost
+	L.evictOverCost()
 
 	return true
 }
 
+// evictOverCost вытесняет элементы с конца очереди, пока суммарная стоимость превышает maxCost
+func (L *LRU) evictOverCost() {
+	if L.maxCost <= 0 {
+		return
+	}
+	for L.totalCost > L.maxCost {
+		L.removeLastElement(cache.EvictedCapacity)
+	}
+}
+
+// Get повышает приоритет найденного элемента. Колбэк доступа элемента вызывается уже после снятия блокировки.
+// В режиме WithDeferredPromotion повышение откладывается, см. getDeferred
 func (L *LRU) Get(key interface{}) (value interface{}, ok bool) {
-	element, exists := L.items[key]
+	if L.promotions != nil {
+		return L.getDeferred(key)
+	}
+
+	L.lock()
+	item, ok := L.get(key)
+	if !ok {
+		L.mu.Unlock()
+		if L.onMiss != nil {
+			L.onMiss(key)
+		}
+		return nil, false
+	}
+	key, value, onAccess := item.Key, item.Value, item.onAccess
+	L.mu.Unlock()
+
+	if L.onHit != nil {
+		L.onHit(key)
+	}
+	if onAccess != nil {
+		onAccess(key, value)
+	}
+	return value, true
+}
+
+// get ищет элемент, учитывает попадание или промах и повышает приоритет найденного элемента
+func (L *LRU) get(key interface{}) (*Item, bool) {
+	element, exists := L.items[L.mapKey(key)]
 	if !exists {
-		return "", false
+		atomic.AddUint64(&L.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&L.hits, 1)
+	item := element.Value.(*Item)
+	if !L.frozen {
+		L.queue.MoveToFront(element)
+		L.touch(item)
+	}
+	return item, true
+}
+
+// Touch повышает приоритет элемента, не читая значение, и сообщает, есть ли ключ в кеше.
+// В отличие от Get, не учитывается в статистике и не вызывает колбэк доступа
+func (L *LRU) Touch(key interface{}) bool {
+	L.lock()
+	defer L.mu.Unlock()
+
+	element, ok := L.items[L.mapKey(key)]
+	if !ok {
+		return false
+	}
+	if !L.frozen {
+		L.queue.MoveToFront(element)
+		L.touch(element.Value.(*Item))
+	}
+	return true
+}
+
+// GetQuiet читает значение, не повышая приоритет элемента. В отличие от Peek, учитывается в статистике
+// и вызывает колбэки WithOnHit и WithOnMiss, поэтому подходит для чтения в циклах, где порядок
+// обновляется выборочно через Promote. Колбэк доступа элемента не вызывается
+func (L *LRU) GetQuiet(key interface{}) (value interface{}, ok bool) {
+	L.lock()
+	element, exists := L.items[L.mapKey(key)]
+	if !exists {
+		atomic.AddUint64(&L.misses, 1)
+		L.mu.Unlock()
+		if L.onMiss != nil {
+			L.onMiss(key)
+		}
+		return nil, false
+	}
+	atomic.AddUint64(&L.hits, 1)
+	key, value = element.Value.(*Item).Key, element.Value.(*Item).Value
+	L.mu.Unlock()
+
+	if L.onHit != nil {
+		L.onHit(key)
+	}
+	return value, true
+}
+
+// Promote отмечает использование элемента после GetQuiet и сообщает, есть ли ключ в кеше. Работает как Touch
+func (L *LRU) Promote(key interface{}) bool {
+	return L.Touch(key)
+}
+
+// Clone возвращает независимую копию кеша с теми же элементами, порядком, настройками и статистикой.
+// Значения копируются поверхностно: указатели и ссылочные типы в копии и оригинале общие.
+// Подписка на события удаления и выполняющиеся загрузки GetOrCompute не копируются
+func (L *LRU) Clone() *LRU {
+	L.lock()
+	defer L.mu.Unlock()
+
+	clone := &LRU{
+		capacity:       L.capacity,
+		lowWatermark:   L.lowWatermark,
+		items:          make(map[interface{}]*list.Element, len(L.items)),
+		queue:          list.New(),
+		defaultFactory: L.defaultFactory,
+		now:            L.now,
+		maxCost:        L.maxCost,
+		totalCost:      L.totalCost,
+		hits:           atomic.LoadUint64(&L.hits),
+		misses:         atomic.LoadUint64(&L.misses),
+		evictions:      atomic.LoadUint64(&L.evictions),
+		frozen:         L.frozen,
+		keyFunc:        L.keyFunc,
+		onHit:          L.onHit,
+		onMiss:         L.onMiss,
+	}
+	if L.promotions != nil {
+		clone.promotions = newPromotionBuffer(len(L.promotions.keys))
+	}
+	for element := L.queue.Front(); element != nil; element = element.Next() {
+		item := *element.Value.(*Item)
+		clone.items[L.mapKey(item.Key)] = clone.queue.PushBack(&item)
+	}
+	for tag, keys := range L.tags {
+		if clone.tags == nil {
+			clone.tags = make(map[string]map[interface{}]struct{}, len(L.tags))
+		}
+		clone.tags[tag] = make(map[interface{}]struct{}, len(keys))
+		for key := range keys {
+			clone.tags[tag][key] = struct{}{}
+		}
+	}
+	return clone
+}
+
+// Freeze переводит кеш в режим только для чтения: добавления (Add, AddWithCost, PutAll, сохранение
+// результатов загрузчиков) и удаления (Remove, RemoveOldest, EvictN) игнорируются и возвращают false или 0,
+// а Get возвращает значения без повышения приоритета. Clear, Resize и Restore остаются доступными
+// как явные административные операции
+func (L *LRU) Freeze() {
+	L.lock()
+	defer L.mu.Unlock()
+	L.frozen = true
+}
+
+// Unfreeze возвращает кеш в обычный режим
+func (L *LRU) Unfreeze() {
+	L.lock()
+	defer L.mu.Unlock()
+	L.frozen = false
+}
+
+// Frozen сообщает, находится ли кеш в режиме только для чтения
+func (L *LRU) Frozen() bool {
+	L.lock()
+	defer L.mu.Unlock()
+	return L.frozen
+}
+
+// touch отмечает обращение к элементу для Metadata
+func (L *LRU) touch(item *Item) {
+	item.accessed = L.now()
+	item.accesses++
+}
+
+// Metadata возвращает время последнего обращения к ключу и число обращений (Get и Add).
+// Сам вызов, как и Peek, обращением не считается
+func (L *LRU) Metadata(key interface{}) (lastAccess time.Time, count int, ok bool) {
+	L.lock()
+	defer L.mu.Unlock()
+
+	element, exists := L.items[L.mapKey(key)]
+	if !exists {
+		return time.Time{}, 0, false
+	}
+	item := element.Value.(*Item)
+	return item.accessed, item.accesses, true
+}
+
+// PutAll добавляет или обновляет все элементы под одной блокировкой.
+// Порядок вставки внутри пачки не определён, как и порядок обхода map
+func (L *LRU) PutAll(items map[interface{}]interface{}) {
+	L.lock()
+	defer L.mu.Unlock()
+	for key, value := range items {
+		L.add(key, value)
+	}
+}
+
+// Merge добавляет в кеш все элементы other в его порядке вытеснения, так что самые приоритетные в other
+// добавляются последними и вытесняются позже остальных. Если ключ уже есть в кеше, сохраняется
+// результат resolve(key, текущее значение, значение из other). Ёмкость и вытеснение работают как при Add.
+// Снимок other берётся до блокировки, а все элементы добавляются под одной блокировкой, поэтому
+// слияние атомарно для других вызывающих. resolve вызывается под блокировкой и не должен обращаться к кешу
+func (L *LRU) Merge(other cache.EntryLister, resolve func(key, a, b interface{}) interface{}) {
+	entries := other.Entries()
+
+	L.lock()
+	defer L.mu.Unlock()
+	for _, entry := range entries {
+		value := entry.Value
+		if element, exists := L.items[L.mapKey(entry.Key)]; exists {
+			value = resolve(entry.Key, element.Value.(*Item).Value, entry.Value)
+		}
+		L.add(entry.Key, value)
+	}
+}
+
+// GetAll возвращает найденные значения для keys под одной блокировкой, повышая их приоритет как Get.
+// Отсутствующие ключи в результат не попадают. Колбэки доступа вызываются после снятия блокировки
+func (L *LRU) GetAll(keys []interface{}) map[interface{}]interface{} {
+	values, _ := L.GetMultiple(keys)
+	return values
+}
+
+// GetMultiple работает как GetAll и дополнительно возвращает ненайденные ключи в порядке запроса,
+// например, чтобы догрузить их из хранилища одним запросом
+func (L *LRU) GetMultiple(keys []interface{}) (values map[interface{}]interface{}, misses []interface{}) {
+	values = make(map[interface{}]interface{}, len(keys))
+	var accessed []Item
+	L.lock()
+	for _, key := range keys {
+		item, ok := L.get(key)
+		if !ok {
+			misses = append(misses, key)
+			continue
+		}
+		values[key] = item.Value
+		if item.onAccess != nil {
+			accessed = append(accessed, *item)
+		}
+	}
+	L.mu.Unlock()
+
+	for _, item := range accessed {
+		item.onAccess(item.Key, item.Value)
+	}
+	return values, misses
+}
+
+// GetWithAge работает как Get и дополнительно возвращает время, прошедшее с добавления или обновления значения.
+// Сам метод ничего не вытесняет, решение об обновлении остаётся за вызывающим
+func (L *LRU) GetWithAge(key interface{}) (value interface{}, age time.Duration, ok bool) {
+	L.lock()
+	item, ok := L.get(key)
+	if !ok {
+		L.mu.Unlock()
+		return nil, 0, false
+	}
+	key, value, onAccess := item.Key, item.Value, item.onAccess
+	age = L.now().Sub(item.updated)
+	L.mu.Unlock()
+
+	if onAccess != nil {
+		onAccess(key, value)
+	}
+	return value, age, true
+}
+
+// GetOrAdd возвращает существующее значение (повышая его приоритет) и loaded=true,
+// либо добавляет value и возвращает его с loaded=false. Проверка и вставка выполняются атомарно
+func (L *LRU) GetOrAdd(key interface{}, value interface{}) (actual interface{}, loaded bool) {
+	L.lock()
+	defer L.mu.Unlock()
+
+	if item, ok := L.get(key); ok {
+		return item.Value, true
+	}
+	L.add(key, value)
+	return value, false
+}
+
+// Replace обновляет значение существующего ключа и повышает его приоритет, как Add.
+// Отсутствующий ключ не добавляется, в этом случае возвращается false
+func (L *LRU) Replace(key, value interface{}) bool {
+	L.lock()
+	defer L.mu.Unlock()
+
+	if _, ok := L.items[L.mapKey(key)]; !ok || L.frozen {
+		return false
+	}
+	L.add(key, value)
+	return true
+}
+
+// AddIfAbsent добавляет value, только если ключа нет в кеше, и возвращает его с loaded=false.
+// Для существующего ключа возвращает текущее значение и loaded=true, не меняя ни значение, ни приоритет.
+// В отличие от GetOrAdd, существующий элемент не продвигается и не учитывается в статистике
+func (L *LRU) AddIfAbsent(key, value interface{}) (actual interface{}, loaded bool) {
+	L.lock()
+	defer L.mu.Unlock()
+
+	if element, ok := L.items[L.mapKey(key)]; ok {
+		return element.Value.(*Item).Value, true
+	}
+	L.add(key, value)
+	return value, false
+}
+
+// GetOrCompute возвращает значение из кеша, а при промахе вызывает loader и сохраняет его результат.
+// loader выполняется без блокировки кеша; при ошибке ничего не сохраняется и ошибка возвращается вызывающему.
+// Результат nil без ошибки кешируется как обычное значение, и loader для этого ключа больше не вызывается.
+// Одновременные промахи по одному ключу объединяются: loader выполняется один раз,
+// остальные вызывающие ждут и получают тот же результат или ту же ошибку.
+// Если loader паникует, загрузка завершается, а panic повторяется у всех ожидавших её вызывающих
+func (L *LRU) GetOrCompute(key interface{}, loader func() (interface{}, error)) (interface{}, error) {
+	value, _, err := L.GetOrComputeOwned(key, loader)
+	return value, err
+}
+
+// GetOrComputeOwned работает как GetOrCompute и дополнительно сообщает computedByUs: true только у того
+// вызывающего, чей loader действительно выполнился. Попадание в кеш и ожидание чужой загрузки дают false,
+// что позволяет относить стоимость загрузки на того, кто её выполнил
+func (L *LRU) GetOrComputeOwned(key interface{}, loader func() (interface{}, error)) (value interface{}, computedByUs bool, err error) {
+	L.lock()
+	if item, ok := L.get(key); ok {
+		key, value, onAccess := item.Key, item.Value, item.onAccess
+		L.mu.Unlock()
+		if onAccess != nil {
+			onAccess(key, value)
+		}
+		return value, false, nil
+	}
+
+	if c, ok := L.inflight[L.mapKey(key)]; ok {
+		L.mu.Unlock()
+		c.wg.Wait()
+		if c.panicValue != nil {
+			panic(c.panicValue)
+		}
+		return c.value, false, c.err
+	}
+
+	c := &call{}
+	c.wg.Add(1)
+	if L.inflight == nil {
+		L.inflight = make(map[interface{}]*call)
+	}
+	L.inflight[L.mapKey(key)] = c
+	L.mu.Unlock()
+
+	c.value, c.err = L.callLoader(key, c, loader)
+	if c.err != nil {
+		c.value = nil
+	}
+
+	L.lock()
+	if c.err == nil {
+		L.add(key, c.value)
+	}
+	delete(L.inflight, L.mapKey(key))
+	L.mu.Unlock()
+	c.wg.Done()
+
+	return c.value, true, c.err
+}
+
+// callLoader вызывает loader и при panic снимает загрузку из inflight и освобождает ожидающих,
+// чтобы следующие вызовы для этого ключа не зависли, после чего повторяет panic
+func (L *LRU) callLoader(key interface{}, c *call, loader func() (interface{}, error)) (interface{}, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.panicValue = r
+			L.lock()
+			delete(L.inflight, L.mapKey(key))
+			L.mu.Unlock()
+			c.wg.Done()
+			panic(r)
+		}
+	}()
+	return loader()
+}
+
+// GetWithContext работает как GetOrCompute, но передаёт ctx в loader и перестаёт ждать загрузку
+// при отмене ctx или истечении его срока, возвращая ctx.Err(). Значение, загруженное после отмены,
+// в кеш не попадает. Одновременные промахи не объединяются: у каждого вызывающего свой контекст
+func (L *LRU) GetWithContext(ctx context.Context, key interface{}, loader func(context.Context) (interface{}, error)) (interface{}, error) {
+	if value, ok := L.Get(key); ok {
+		return value, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		value interface{}
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := loader(ctx)
+		done <- result{value, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		L.lock()
+		defer L.mu.Unlock()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		L.add(key, r.value)
+		return r.value, nil
+	}
+}
+
+// AddWithOnAccess работает как Add и привязывает к элементу колбэк, вызываемый при каждом Get этого ключа
+func (L *LRU) AddWithOnAccess(key, value interface{}, onAccess func(key, value interface{})) bool {
+	L.lock()
+	defer L.mu.Unlock()
+
+	if L.frozen {
+		return false
+	}
+	ok := L.add(key, value)
+	if element, exists := L.items[L.mapKey(key)]; exists {
+		element.Value.(*Item).onAccess = onAccess
+	}
+	return ok
+}
+
+// Peek возвращает значение без повышения приоритета и без вызова колбэков доступа
+func (L *LRU) Peek(key interface{}) (value interface{}, ok bool) {
+	L.lock()
+	defer L.mu.Unlock()
+
+	element, exists := L.items[L.mapKey(key)]
+	if !exists {
+		return nil, false
 	}
-	L.queue.MoveToFront(element)
 	return element.Value.(*Item).Value, true
 }
 
+// PeekOldest возвращает наименее приоритетный элемент (следующий кандидат на вытеснение) без повышения приоритета
+func (L *LRU) PeekOldest() (key, value interface{}, ok bool) {
+	L.lock()
+	defer L.mu.Unlock()
+	return peekElement(L.queue.Back())
+}
+
+// PeekNewest возвращает самый недавно использованный элемент без изменения порядка
+func (L *LRU) PeekNewest() (key, value interface{}, ok bool) {
+	L.lock()
+	defer L.mu.Unlock()
+	return peekElement(L.queue.Front())
+}
+
+func peekElement(element *list.Element) (key, value interface{}, ok bool) {
+	if element == nil {
+		return nil, nil, false
+	}
+	item := element.Value.(*Item)
+	return item.Key, item.Value, true
+}
+
+// GetOrDefaultFactory возвращает значение из кеша, а при промахе - результат фабрики по умолчанию.
+// Результат фабрики в кеш не сохраняется; без фабрики при промахе возвращается nil
+func (L *LRU) GetOrDefaultFactory(key interface{}) interface{} {
+	if value, ok := L.Get(key); ok {
+		return value
+	}
+	if L.defaultFactory == nil {
+		return nil
+	}
+	return L.defaultFactory(key)
+}
+
 func (L *LRU) Remove(key interface{}) (ok bool) {
-	element, exists := L.items[key]
+	L.lock()
+	defer L.mu.Unlock()
+	if L.frozen {
+		return false
+	}
+
+	element, exists := L.items[L.mapKey(key)]
 	if exists {
-		L.queue.Remove(element)
-		delete(L.items, key)
+		L.removeElement(element)
 		return true
 	} else {
 		return false
 	}
 }
 
-func (L *LRU) removeLastElement() {
-	if element := L.queue.Back(); element != nil {
-		item := L.queue.Remove(element).(*Item)
-		delete(L.items, item.Key)
+// AddWithTags работает как Add и помечает элемент тегами для InvalidateTag.
+// Теги существующего ключа заменяются, обычный Add их сохраняет
+func (L *LRU) AddWithTags(key, value interface{}, tags ...string) bool {
+	L.lock()
+	defer L.mu.Unlock()
+	if L.frozen {
+		return false
+	}
+
+	ok := L.add(key, value)
+	element, exists := L.items[L.mapKey(key)]
+	if !exists {
+		return ok
+	}
+	item := element.Value.(*Item)
+	L.untag(item)
+	item.tags = append([]string(nil), tags...)
+	for _, tag := range item.tags {
+		if L.tags == nil {
+			L.tags = make(map[string]map[interface{}]struct{})
+		}
+		if L.tags[tag] == nil {
+			L.tags[tag] = make(map[interface{}]struct{})
+		}
+		L.tags[tag][L.mapKey(key)] = struct{}{}
+	}
+	return ok
+}
+
+// InvalidateTag удаляет все элементы с тегом tag и возвращает их количество
+func (L *LRU) InvalidateTag(tag string) int {
+	L.lock()
+	defer L.mu.Unlock()
+	if L.frozen {
+		return 0
+	}
+
+	removed := 0
+	for key := range L.tags[tag] {
+		L.removeElement(L.items[key])
+		removed++
+	}
+	return removed
+}
+
+// untag убирает элемент из индекса тегов
+func (L *LRU) untag(item *Item) {
+	for _, tag := range item.tags {
+		delete(L.tags[tag], L.mapKey(item.Key))
+		if len(L.tags[tag]) == 0 {
+			delete(L.tags, tag)
+		}
+	}
+}
+
+// RemovePrefix удаляет все элементы со строковыми ключами, начинающимися с prefix, и возвращает их количество.
+// Ключи других типов не затрагиваются
+func (L *LRU) RemovePrefix(prefix string) int {
+	L.lock()
+	defer L.mu.Unlock()
+	if L.frozen {
+		return 0
+	}
+
+	removed := 0
+	for _, element := range L.items {
+		if s, ok := element.Value.(*Item).Key.(string); ok && strings.HasPrefix(s, prefix) {
+			L.removeElement(element)
+			removed++
+		}
+	}
+	return removed
+}
+
+// removeElement явно удаляет элемент из кеша
+func (L *LRU) removeElement(element *list.Element) {
+	item := L.queue.Remove(element).(*Item)
+	delete(L.items, L.mapKey(item.Key))
+	L.untag(item)
+	L.totalCost -= item.cost
+	L.emit(item, cache.EvictedManual)
+}
+
+func (L *LRU) Len() int {
+	L.lock()
+	defer L.mu.Unlock()
+	return L.queue.Len()
+}
+
+// Cap возвращает текущую ёмкость кеша с учётом Resize.
+// Для кеша, ограниченного стоимостью, количество элементов не ограничено и возвращается 0
+func (L *LRU) Cap() int {
+	L.lock()
+	defer L.mu.Unlock()
+	if L.maxCost > 0 {
+		return 0
+	}
+	return L.capacity
+}
+
+// Keys возвращает снимок ключей от самого недавно использованного к наименее приоритетному
+func (L *LRU) Keys() []interface{} {
+	L.lock()
+	defer L.mu.Unlock()
+
+	keys := make([]interface{}, 0, L.queue.Len())
+	for element := L.queue.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*Item).Key)
+	}
+	return keys
+}
+
+// Entries возвращает согласованный снимок элементов от первого кандидата на вытеснение
+// к самому недавно использованному. Rank - позиция по давности обращения: 0 у самого недавнего элемента.
+// Приоритеты элементов не меняются
+func (L *LRU) Entries() []cache.Entry {
+	L.lock()
+	defer L.mu.Unlock()
+
+	entries := make([]cache.Entry, 0, L.queue.Len())
+	rank := L.queue.Len() - 1
+	for element := L.queue.Back(); element != nil; element = element.Prev() {
+		item := element.Value.(*Item)
+		entries = append(entries, cache.Entry{Key: item.Key, Value: item.Value, Rank: rank})
+		rank--
+	}
+	return entries
+}
+
+// ToMap возвращает копию всех элементов кеша. Приоритеты элементов не меняются
+func (L *LRU) ToMap() map[interface{}]interface{} {
+	L.lock()
+	defer L.mu.Unlock()
+
+	m := make(map[interface{}]interface{}, len(L.items))
+	for _, element := range L.items {
+		item := element.Value.(*Item)
+		m[item.Key] = item.Value
+	}
+	return m
+}
+
+// Values возвращает снимок значений в том же порядке, что и Keys. Приоритеты элементов не меняются
+func (L *LRU) Values() []interface{} {
+	L.lock()
+	defer L.mu.Unlock()
+
+	values := make([]interface{}, 0, L.queue.Len())
+	for element := L.queue.Front(); element != nil; element = element.Next() {
+		values = append(values, element.Value.(*Item).Value)
+	}
+	return values
+}
+
+// Range вызывает f для каждого элемента от самого недавно использованного, пока f возвращает true.
+// Порядок и статистика не меняются. f вызывается по снимку, снятому под блокировкой, уже без неё,
+// поэтому может обращаться к кешу, но не увидит изменений, сделанных во время обхода
+func (L *LRU) Range(f func(key, value interface{}) bool) {
+	L.lock()
+	items := make([]Item, 0, L.queue.Len())
+	for element := L.queue.Front(); element != nil; element = element.Next() {
+		items = append(items, *element.Value.(*Item))
+	}
+	L.mu.Unlock()
+
+	for _, item := range items {
+		if !f(item.Key, item.Value) {
+			return
+		}
+	}
+}
+
+// String возвращает содержимое кэша от наиболее к наименее приоритетным элементам в виде [k=v, ...].
+// Порядок элементов не меняется
+func (L *LRU) String() string {
+	L.lock()
+	defer L.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for element := L.queue.Front(); element != nil; element = element.Next() {
+		if element != L.queue.Front() {
+			b.WriteString(", ")
+		}
+		item := element.Value.(*Item)
+		fmt.Fprintf(&b, "%v=%v", item.Key, item.Value)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// TotalCost возвращает суммарную стоимость элементов в кеше
+func (L *LRU) TotalCost() int64 {
+	L.lock()
+	defer L.mu.Unlock()
+	return L.totalCost
+}
+
+// snapshotEntry - сериализуемая пара ключ-значение снимка
+type snapshotEntry struct {
+	Key   interface{} `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// Snapshot сериализует элементы в JSON в порядке от самого недавно использованного.
+// Ключи и значения должны кодироваться в JSON, иначе возвращается ошибка кодирования
+func (L *LRU) Snapshot() ([]byte, error) {
+	L.lock()
+	entries := make([]snapshotEntry, 0, L.queue.Len())
+	for element := L.queue.Front(); element != nil; element = element.Next() {
+		item := element.Value.(*Item)
+		entries = append(entries, snapshotEntry{Key: item.Key, Value: item.Value})
+	}
+	L.mu.Unlock()
+
+	return json.Marshal(entries)
+}
+
+// Restore заменяет содержимое кеша снимком из Snapshot, восстанавливая порядок использования.
+// Если элементов больше ёмкости, сохраняются самые недавние. Значения восстанавливаются
+// по правилам encoding/json (например, числа становятся float64), поэтому составные ключи недопустимы.
+// Работает и в режиме только для чтения
+func (L *LRU) Restore(data []byte) error {
+	var entries []snapshotEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
 	}
+
+	L.lock()
+	defer L.mu.Unlock()
+
+	L.items = make(map[interface{}]*list.Element, L.capacity)
+	L.queue.Init()
+	L.tags = nil
+	L.totalCost = 0
+	// Элементы вставляются напрямую через insert: add в режиме только для чтения ничего не делает
+	for i := len(entries) - 1; i >= 0; i-- {
+		if _, exists := L.items[L.mapKey(entries[i].Key)]; !exists {
+			L.insert(entries[i].Key, entries[i].Value, 1)
+		}
+	}
+	return nil
+}
+
+// Stats возвращает количество попаданий и промахов Get
+func (L *LRU) Stats() (hits, misses uint64) {
+	return atomic.LoadUint64(&L.hits), atomic.LoadUint64(&L.misses)
+}
+
+// Evictions возвращает количество элементов, вытесненных из кэша
+func (L *LRU) Evictions() uint64 {
+	return atomic.LoadUint64(&L.evictions)
 }
 
-func NewLRUCache(n int) cache.Cache {
+// HitRatio возвращает долю попаданий среди всех Get, 0 если обращений не было
+func (L *LRU) HitRatio() float64 {
+	hits, misses := L.Stats()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// Clear удаляет все элементы, сохраняя ёмкость. Очистка не считается вытеснением
+func (L *LRU) Clear() {
+	L.lock()
+	defer L.mu.Unlock()
+
+	L.items = make(map[interface{}]*list.Element, L.capacity)
+	L.queue.Init()
+	L.tags = nil
+	L.totalCost = 0
+}
+
+// Flush атомарно удаляет из кеша все элементы и возвращает их в том же порядке, что и Keys,
+// например, чтобы при остановке сохранить оставшиеся элементы в другом месте.
+// Как и Clear, работает и в режиме только для чтения. При notify=true о каждом элементе
+// отправляется событие удаления с причиной cache.EvictedManual
+func (L *LRU) Flush(notify bool) []cache.KV {
+	L.lock()
+	defer L.mu.Unlock()
+
+	entries := make([]cache.KV, 0, L.queue.Len())
+	for element := L.queue.Front(); element != nil; element = element.Next() {
+		item := element.Value.(*Item)
+		entries = append(entries, cache.KV{Key: item.Key, Value: item.Value})
+		if notify {
+			L.emit(item, cache.EvictedManual)
+		}
+	}
+
+	L.items = make(map[interface{}]*list.Element, L.capacity)
+	L.queue.Init()
+	L.tags = nil
+	L.totalCost = 0
+	return entries
+}
+
+// Resize меняет ёмкость кеша и при уменьшении вытесняет наименее приоритетные элементы.
+// Возвращает количество вытесненных элементов. Как и конструктор, паникует на отрицательной ёмкости.
+// Для кеша, ограниченного стоимостью, количество элементов не ограничивается и Resize ничего не вытесняет
+func (L *LRU) Resize(newCapacity int) int {
+	if newCapacity < 0 {
+		panic("capacity must not be negative")
+	}
+
+	L.lock()
+	defer L.mu.Unlock()
+
+	L.capacity = newCapacity
+	if L.lowWatermark > newCapacity {
+		L.lowWatermark = newCapacity
+	}
+
+	evicted := 0
+	for L.maxCost == 0 && L.queue.Len() > L.capacity {
+		L.removeLastElement(cache.EvictedCapacity)
+		evicted++
+	}
+	return evicted
+}
+
+// RemoveOldest вытесняет наименее приоритетный элемент и возвращает его. Для пустого кэша ok=false
+func (L *LRU) RemoveOldest() (key, value interface{}, ok bool) {
+	L.lock()
+	defer L.mu.Unlock()
+	if L.frozen {
+		return nil, nil, false
+	}
+	item := L.removeLastElement(cache.EvictedManual)
+	if item == nil {
+		return nil, nil, false
+	}
+	return item.Key, item.Value, true
+}
+
+// EvictN вытесняет до n наименее приоритетных элементов и возвращает, сколько удалось удалить
+func (L *LRU) EvictN(n int) int {
+	L.lock()
+	defer L.mu.Unlock()
+	evicted := 0
+	for !L.frozen && evicted < n && L.removeLastElement(cache.EvictedManual) != nil {
+		evicted++
+	}
+	return evicted
+}
+
+// removeLastElement удаляет последний элемент очереди и возвращает его (nil для пустого кэша)
+func (L *LRU) removeLastElement(reason cache.EvictionReason) *Item {
+	element := L.queue.Back()
+	if element == nil {
+		return nil
+	}
+	item := L.queue.Remove(element).(*Item)
+	delete(L.items, L.mapKey(item.Key))
+	L.untag(item)
+	L.totalCost -= item.cost
+	atomic.AddUint64(&L.evictions, 1)
+	L.emit(item, reason)
+	return item
+}
+
+// EvictionEvents возвращает канал событий об удалённых элементах, при первом вызове создавая его
+// с буфером cache.EventBufferSize. События о вытеснении и явном удалении (Remove, RemoveOldest, EvictN)
+// отправляются без блокировки: при заполненном буфере они отбрасываются. Clear событий не порождает
+func (L *LRU) EvictionEvents() <-chan cache.EvictedEntry {
+	L.lock()
+	defer L.mu.Unlock()
+	if L.closed {
+		return closedEvents()
+	}
+	if L.events == nil {
+		L.events = make(chan cache.EvictedEntry, cache.EventBufferSize)
+	}
+	return L.events
+}
+
+// Close освобождает ресурсы кеша: закрывает канал событий удаления, а последующие вызовы
+// EvictionEvents возвращают уже закрытый канал. Фоновых горутин у кеша нет, поэтому остальные
+// операции после Close продолжают работать. Повторный вызов безопасен и возвращает nil
+func (L *LRU) Close() error {
+	L.lock()
+	defer L.mu.Unlock()
+	L.closed = true
+	if L.events != nil {
+		close(L.events)
+		L.events = nil
+	}
+	return nil
+}
+
+// closedEvents возвращает закрытый канал событий для подписчиков закрытого кеша
+func closedEvents() <-chan cache.EvictedEntry {
+	events := make(chan cache.EvictedEntry)
+	close(events)
+	return events
+}
+
+// StopEvictionEvents закрывает канал событий. Следующий вызов EvictionEvents создаст новый канал
+func (L *LRU) StopEvictionEvents() {
+	L.lock()
+	defer L.mu.Unlock()
+	if L.events != nil {
+		close(L.events)
+		L.events = nil
+	}
+}
+
+// emit отправляет событие об удалении элемента, если на события кто-то подписан
+func (L *LRU) emit(item *Item, reason cache.EvictionReason) {
+	if L.events == nil {
+		return
+	}
+	select {
+	case L.events <- cache.EvictedEntry{Key: item.Key, Value: item.Value, Reason: reason}:
+	default:
+	}
+}
+
+// NewLRUCacheWithMaxCost создает LRU кеш, ограниченный суммарной стоимостью элементов, а не их количеством.
+// Элементы, добавленные через Add, имеют стоимость 1
+func NewLRUCacheWithMaxCost(maxCost int64, opts ...Option) cache.Cache {
+	if maxCost <= 0 {
+		panic("max cost must be positive")
+	}
+	L := NewLRU(0, opts...)
+	L.maxCost = maxCost
+	return L
+}
+
+// NewLRUCacheWatermark создает LRU кеш, который вытесняет элементы пачками: кеш заполняется до high
+// элементов, а добавление нового ключа в полный кеш вытесняет наименее приоритетные элементы так,
+// чтобы вместе с новым осталось low. Это амортизирует затраты на вытеснение, но в среднем кеш хранит меньше элементов.
+// При low == high поведение совпадает с NewLRUCache(high)
+func NewLRUCacheWatermark(high, low int, opts ...Option) cache.Cache {
+	if low <= 0 || low > high {
+		panic("watermarks must satisfy 0 < low <= high")
+	}
+	L := NewLRU(high, opts...)
+	L.lowWatermark = low
+	return L
+}
+
+// NewLRUCache создает LRU кеш ёмкостью n и возвращает его как cache.Cache
+func NewLRUCache(n int, opts ...Option) cache.Cache {
+	return NewLRU(n, opts...)
+}
+
+// NewLRUCacheE работает как NewLRUCache, но вместо panic возвращает ошибку для n <= 0.
+// Предназначен для ёмкости из пользовательской конфигурации, где ноль скорее ошибка;
+// кеш нулевой ёмкости по-прежнему создаёт NewLRUCache(0)
+func NewLRUCacheE(n int, opts ...Option) (cache.Cache, error) {
 	if n <= 0 {
-		panic("capacity must be positive")
+		return nil, fmt.Errorf("lru: capacity must be positive, got %d", n)
 	}
-	return &LRU{
+	return NewLRU(n, opts...), nil
+}
+
+// NewLRUFromMap создает LRU кеш ёмкостью n и загружает в него элементы m.
+// Порядок обхода map не определён, поэтому при len(m) > n неизвестно, какие элементы будут вытеснены
+func NewLRUFromMap(n int, m map[interface{}]interface{}, opts ...Option) *LRU {
+	L := NewLRU(n, opts...)
+	L.PutAll(m)
+	return L
+}
+
+// NewLRU работает как NewLRUCache, но возвращает конкретный тип, чтобы без приведения типа
+// пользоваться методами вне интерфейса cache.Cache (Peek, Keys, Stats и др.)
+func NewLRU(n int, opts ...Option) *LRU {
+	if n < 0 {
+		panic("capacity must not be negative")
+	}
+	L := &LRU{
 		capacity: n,
-		items:    make(map[interface{}]*list.Element),
+		items:    make(map[interface{}]*list.Element, n),
 		queue:    list.New(),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(L)
 	}
+	return L
 }
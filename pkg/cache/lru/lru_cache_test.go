@@ -1,14 +1,21 @@
 package lru
 
 import (
+	"LRU_cache/pkg/cache"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 // Тест: корректное добавление элемента в пустой кеш
 func TestLRU_Add_NewElement(t *testing.T) {
-	lru := NewLRUCache(2).(*LRU)
+	lru := NewLRU(2)
 
 	ok := lru.Add("key1", "value1")
 
@@ -17,19 +24,36 @@ func TestLRU_Add_NewElement(t *testing.T) {
 	assert.Contains(t, lru.items, "key1", "Items map should contain key1")
 }
 
-// Тест: добавление существующего ключа (должен вернуть false, переместить в начало)
+// Тест: добавление существующего ключа (должен вернуть false, обновить значение, переместить в начало)
 func TestLRU_Add_ExistingKey(t *testing.T) {
-	lru := NewLRUCache(2).(*LRU)
+	lru := NewLRU(2)
 	lru.Add("key1", "value1")
 
 	ok := lru.Add("key1", "value2")
 
 	assert.False(t, ok, "Expected Add to return false for existing key")
-	assert.Equal(t, "value1", lru.queue.Front().Value.(*Item).Value,
-		"Value should not be updated on Add for existing key")
+	assert.Equal(t, "value2", lru.queue.Front().Value.(*Item).Value,
+		"Value should be updated on Add for existing key")
 	assert.Equal(t, 1, lru.queue.Len(), "Queue length should still be 1")
 }
 
+// Тест: повторный Add заменяет значение и повышает приоритет ключа
+func TestLRU_Add_ExistingKey_ReplacesValue(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+
+	lru.Add("key1", "updated") // очередь: [key1, key2]
+
+	val, ok := lru.Get("key1")
+	assert.True(t, ok, "key1 should be present")
+	assert.Equal(t, "updated", val, "Get should return the replaced value")
+
+	lru.Add("key3", "value3") // key2 должен вытесниться
+	_, ok = lru.Get("key2")
+	assert.False(t, ok, "key2 should be evicted after key1 was refreshed")
+}
+
 // Тест: превышение ёмкости (LRU-вытеснение)
 func TestLRU_Add_Overflow(t *testing.T) {
 	lru := NewLRUCache(2).(*LRU)
@@ -72,7 +96,11 @@ func TestLRU_Get_NonExistent(t *testing.T) {
 
 	val, ok := lru.Get("unknown")
 	assert.False(t, ok, "Get should return false for unknown key")
-	assert.Empty(t, val, "Value should be empty")
+	assert.Nil(t, val, "Value should be nil")
+
+	val, ok = lru.Peek("unknown")
+	assert.False(t, ok, "Peek should return false for unknown key")
+	assert.Nil(t, val, "Peek value should be nil")
 }
 
 // Тест: Remove существующего элемента
@@ -106,6 +134,274 @@ func TestLRU_ZeroCapacity(t *testing.T) {
 	assert.Equal(t, 0, lru.queue.Len(), "Queue should remain empty")
 }
 
+// Тест: AddEvict возвращает вытесненный элемент только при переполнении
+func TestLRU_AddEvict(t *testing.T) {
+	lru := NewLRU(2)
+
+	_, _, evicted := lru.AddEvict("key1", "value1")
+	assert.False(t, evicted, "No eviction while there is room")
+	lru.AddEvict("key2", "value2")
+
+	_, _, evicted = lru.AddEvict("key1", "updated")
+	assert.False(t, evicted, "Updating an existing key should not evict")
+
+	key, value, evicted := lru.AddEvict("key3", "value3")
+	assert.True(t, evicted)
+	assert.Equal(t, "key2", key)
+	assert.Equal(t, "value2", value)
+	assert.Equal(t, []interface{}{"key3", "key1"}, lru.Keys())
+
+	costly := NewLRUCacheWithMaxCost(3).(*LRU)
+	costly.AddWithCost("a", 1, 2)
+	costly.AddWithCost("b", 2, 1)
+	_, _, evicted = costly.AddEvict("a", 10)
+	assert.False(t, evicted)
+	key, _, evicted = costly.AddEvict("c", 3)
+	assert.True(t, evicted)
+	assert.Equal(t, "b", key, "The least recently used entry is evicted first")
+}
+
+// Тест: WithKeyFunc позволяет использовать несравнимые ключи []byte
+func TestLRU_KeyFunc_ByteSliceKeys(t *testing.T) {
+	assert.Panics(t, func() { NewLRU(2).Add([]byte("key1"), "value1") }, "[]byte keys are not comparable")
+
+	var accessed interface{}
+	lru := NewLRU(2, WithKeyFunc(func(key interface{}) string { return string(key.([]byte)) }))
+	lru.AddWithOnAccess([]byte("key1"), "value1", func(key, value interface{}) { accessed = key })
+	assert.False(t, lru.Add([]byte("key1"), "updated"), "Equal byte slices should be the same key")
+
+	val, ok := lru.Get([]byte("key1"))
+	assert.True(t, ok)
+	assert.Equal(t, "updated", val)
+	assert.Equal(t, []byte("key1"), accessed, "Callbacks should receive the original key")
+
+	lru.Add([]byte("key2"), "value2")
+	lru.Add([]byte("key3"), "value3")
+	_, ok = lru.Peek([]byte("key1"))
+	assert.False(t, ok, "key1 should be evicted")
+	assert.Equal(t, []interface{}{[]byte("key3"), []byte("key2")}, lru.Keys())
+
+	// Обновление последнего элемента ничего не вытесняет
+	_, _, evicted := lru.AddEvict([]byte("key2"), "updated2")
+	assert.False(t, evicted)
+	key, _, evicted := lru.AddEvict([]byte("key4"), "value4")
+	assert.True(t, evicted)
+	assert.Equal(t, []byte("key3"), key, "Original key should be kept on the item")
+
+	assert.True(t, lru.Remove([]byte("key2")))
+	assert.Equal(t, 1, lru.Len())
+}
+
+// Тест: хуки OnHit и OnMiss вызываются из Get без удержания блокировки
+func TestLRU_OnHitOnMiss(t *testing.T) {
+	var hits, misses []interface{}
+	var lru *LRU
+	lru = NewLRU(2,
+		WithOnHit(func(key interface{}) {
+			hits = append(hits, key)
+			lru.Len() // хук может обращаться к кешу
+		}),
+		WithOnMiss(func(key interface{}) { misses = append(misses, key) }),
+	)
+	lru.Add("key1", "value1")
+
+	lru.Get("key1")
+	lru.Get("absent")
+	lru.Get("key1")
+	lru.Peek("absent")
+
+	assert.Equal(t, []interface{}{"key1", "key1"}, hits)
+	assert.Equal(t, []interface{}{"absent"}, misses, "Only Get should trigger hooks")
+}
+
+// Тест: Flush возвращает элементы в порядке Keys и оставляет кеш пустым
+func TestLRU_Flush(t *testing.T) {
+	lru := NewLRU(3)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Get("a")
+	events := lru.EvictionEvents()
+
+	entries := lru.Flush(false)
+	assert.Equal(t, []cache.KV{{Key: "a", Value: 1}, {Key: "c", Value: 3}, {Key: "b", Value: 2}}, entries)
+	assert.Equal(t, 0, lru.Len())
+	_, ok := lru.Peek("a")
+	assert.False(t, ok)
+	assert.Len(t, events, 0, "Flush without notify should not emit events")
+
+	lru.Add("d", 4)
+	assert.Equal(t, []cache.KV{{Key: "d", Value: 4}}, lru.Flush(true))
+	assert.Equal(t, cache.EvictedEntry{Key: "d", Value: 4, Reason: cache.EvictedManual}, <-events)
+	assert.Empty(t, lru.Flush(false))
+}
+
+// Тест: кеш с порогами заполняется до high, а затем вытесняет элементы пачкой до low
+func TestLRU_Watermark(t *testing.T) {
+	lru := NewLRUCacheWatermark(5, 2).(*LRU)
+	for i := 0; i < 5; i++ {
+		lru.Add(i, i)
+	}
+	assert.Equal(t, 5, lru.Len(), "Cache should fill up to the high watermark")
+	assert.Equal(t, uint64(0), lru.Evictions())
+
+	lru.Add(5, 5)
+	assert.Equal(t, 2, lru.Len(), "Overflow should evict down to the low watermark")
+	assert.Equal(t, uint64(4), lru.Evictions())
+	assert.Equal(t, []interface{}{5, 4}, lru.Keys(), "The most recent entries should survive")
+
+	for i := 6; i < 100; i++ {
+		lru.Add(i, i)
+		assert.LessOrEqual(t, lru.Len(), 5)
+		assert.GreaterOrEqual(t, lru.Len(), 2)
+	}
+
+	assert.Panics(t, func() { NewLRUCacheWatermark(5, 6) })
+	assert.Panics(t, func() { NewLRUCacheWatermark(5, 0) })
+}
+
+// Тест: Close закрывает канал событий, повторный вызов безопасен
+func TestLRU_Close(t *testing.T) {
+	var _ io.Closer = (*LRU)(nil)
+
+	lru := NewLRU(1)
+	events := lru.EvictionEvents()
+	assert.NoError(t, lru.Close())
+	assert.NoError(t, lru.Close(), "Double close should be safe")
+
+	_, open := <-events
+	assert.False(t, open, "Close should close the event channel")
+	_, open = <-lru.EvictionEvents()
+	assert.False(t, open, "Subscribing after Close should return a closed channel")
+
+	lru.Add("a", 1)
+	lru.Add("b", 2) // вытеснение после Close не отправляет событий
+	val, ok := lru.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+}
+
+// Тест: Entries возвращает элементы от кандидата на вытеснение к самому недавнему с позицией по давности
+func TestLRU_Entries(t *testing.T) {
+	lru := NewLRU(3)
+	assert.Empty(t, lru.Entries())
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Get("a")
+
+	assert.Equal(t, []cache.Entry{
+		{Key: "b", Value: 2, Rank: 2},
+		{Key: "c", Value: 3, Rank: 1},
+		{Key: "a", Value: 1, Rank: 0},
+	}, lru.Entries())
+	assert.Equal(t, []interface{}{"a", "c", "b"}, lru.Keys(), "Entries should not change the order")
+}
+
+// Тест: GetQuiet читает без изменения порядка и учитывается в статистике, Promote переносит элемент в начало
+func TestLRU_GetQuietAndPromote(t *testing.T) {
+	lru := NewLRU(3)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	val, ok := lru.GetQuiet("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	_, ok = lru.GetQuiet("missing")
+	assert.False(t, ok)
+	assert.Equal(t, []interface{}{"c", "b", "a"}, lru.Keys(), "GetQuiet should not change the order")
+	hits, misses := lru.Stats()
+	assert.Equal(t, uint64(1), hits)
+	assert.Equal(t, uint64(1), misses)
+
+	assert.True(t, lru.Promote("a"))
+	assert.False(t, lru.Promote("missing"))
+	assert.Equal(t, []interface{}{"a", "c", "b"}, lru.Keys())
+
+	lru.Add("d", 4)
+	_, ok = lru.Peek("b")
+	assert.False(t, ok, "b should be evicted after a was promoted")
+}
+
+// Тест: Cap возвращает ёмкость из конструктора и обновляется после Resize
+func TestLRU_Cap(t *testing.T) {
+	lru := NewLRU(5)
+	assert.Equal(t, 5, lru.Cap())
+
+	lru.Resize(2)
+	assert.Equal(t, 2, lru.Cap())
+
+	assert.Equal(t, 0, NewLRUCacheWithMaxCost(10).(*LRU).Cap())
+}
+
+var _ cache.EntryLister = (*LRU)(nil)
+
+// Тест: Merge без общих ключей добавляет элементы other в его порядке вытеснения с учётом ёмкости
+func TestLRU_Merge_Disjoint(t *testing.T) {
+	lru := NewLRU(3)
+	lru.Add("a", 1)
+
+	other := NewLRU(3)
+	other.Add("x", 10)
+	other.Add("y", 20)
+	other.Add("z", 30)
+	other.Get("x")
+
+	lru.Merge(other, func(key, a, b interface{}) interface{} {
+		t.Fatalf("resolve should not be called for disjoint caches, got key %v", key)
+		return nil
+	})
+	assert.Equal(t, []interface{}{"x", "z", "y"}, lru.Keys(), "a should be evicted, other's order kept")
+}
+
+// Тест: Merge сохраняет для общих ключей результат resolve
+func TestLRU_Merge_Conflict(t *testing.T) {
+	lru := NewLRU(3)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+
+	other := NewLRU(3)
+	other.Add("b", 20)
+	other.Add("c", 30)
+
+	lru.Merge(other, func(key, a, b interface{}) interface{} {
+		assert.Equal(t, "b", key)
+		return a.(int) + b.(int)
+	})
+
+	val, _ := lru.Peek("b")
+	assert.Equal(t, 22, val)
+	assert.Equal(t, 3, lru.Len())
+}
+
+// Тест: слияние кеша с самим собой не блокируется и разрешает каждый ключ через resolve
+func TestLRU_Merge_Self(t *testing.T) {
+	lru := NewLRU(2)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+
+	lru.Merge(lru, func(key, a, b interface{}) interface{} {
+		return a.(int) * 10
+	})
+	assert.Equal(t, map[interface{}]interface{}{"a": 10, "b": 20}, lru.ToMap())
+}
+
+// Тест: NewLRUCacheE возвращает ошибку вместо panic для неположительной ёмкости
+func TestNewLRUCacheE(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		c, err := NewLRUCacheE(n)
+		assert.Error(t, err, "Capacity %d should be rejected", n)
+		assert.Nil(t, c)
+	}
+
+	c, err := NewLRUCacheE(2)
+	assert.NoError(t, err)
+	assert.True(t, c.Add("key1", "value1"))
+	assert.Equal(t, 1, c.Len())
+}
+
 // Тест: ёмкость 1 — замена элемента
 func TestLRU_CapacityOne_Replace(t *testing.T) {
 	lru := NewLRUCache(1).(*LRU)
@@ -174,3 +470,1204 @@ func TestLRU_MultipleOperations(t *testing.T) {
 	front := lru.queue.Front().Value.(*Item).Key
 	assert.Equal(t, "a", front, "a should be at front after access")
 }
+
+// Тест: Len отражает количество элементов после добавлений, чтений и вытеснений
+func TestLRU_Len(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	assert.Equal(t, 0, lru.Len(), "Empty cache should have zero length")
+
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+	assert.Equal(t, 2, lru.Len())
+
+	lru.Get("key1")
+	lru.Get("unknown")
+	assert.Equal(t, 2, lru.Len(), "Get should not change length")
+
+	lru.Add("key3", "value3") // вытесняется key2
+	assert.Equal(t, 2, lru.Len(), "Length should be capped at capacity")
+
+	lru.Remove("key1")
+	assert.Equal(t, 1, lru.Len())
+}
+
+// Тест: фабрика по умолчанию вызывается только при промахе, результат не кешируется
+func TestLRU_GetOrDefaultFactory(t *testing.T) {
+	calls := 0
+	lru := NewLRUCache(2, WithDefaultFactory(func(key interface{}) interface{} {
+		calls++
+		return "default:" + key.(string)
+	})).(*LRU)
+	lru.Add("key1", "value1")
+
+	assert.Equal(t, "value1", lru.GetOrDefaultFactory("key1"), "Hit should return cached value")
+	assert.Equal(t, 0, calls, "Factory should not be called on hit")
+
+	assert.Equal(t, "default:key2", lru.GetOrDefaultFactory("key2"), "Miss should return factory value")
+	assert.Equal(t, 1, calls, "Factory should be called once on miss")
+
+	_, ok := lru.Get("key2")
+	assert.False(t, ok, "Factory value should not be cached")
+	assert.Equal(t, 1, lru.Len())
+
+	lru.GetOrDefaultFactory("key2")
+	assert.Equal(t, 2, calls, "Factory should be called on every miss")
+}
+
+// Тест: без фабрики промах возвращает nil
+func TestLRU_GetOrDefaultFactory_NoFactory(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+
+	assert.Nil(t, lru.GetOrDefaultFactory("unknown"))
+}
+
+// Тест: Clear очищает кеш и сохраняет ёмкость
+func TestLRU_Clear(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+
+	lru.Clear()
+
+	assert.Equal(t, 0, lru.Len(), "Cache should be empty after Clear")
+	_, ok := lru.Get("key1")
+	assert.False(t, ok, "key1 should miss after Clear")
+	_, ok = lru.Get("key2")
+	assert.False(t, ok, "key2 should miss after Clear")
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	assert.Equal(t, 2, lru.Len(), "Capacity should be preserved after Clear")
+}
+
+// Тест: колбэк доступа вызывается на Get только для своего элемента
+func TestLRU_AddWithOnAccess(t *testing.T) {
+	lru := NewLRUCache(3).(*LRU)
+	var accessed []interface{}
+	onAccess := func(key, value interface{}) {
+		accessed = append(accessed, key, value)
+	}
+
+	lru.AddWithOnAccess("key1", "value1", onAccess)
+	lru.Add("key2", "value2")
+
+	val, ok := lru.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+	assert.Equal(t, []interface{}{"key1", "value1"}, accessed, "Callback should fire on Get")
+
+	lru.Get("key2")
+	lru.Get("unknown")
+	assert.Len(t, accessed, 2, "Callback should fire only for the entry it was attached to")
+}
+
+// Тест: Peek не вызывает колбэк доступа и не меняет порядок
+func TestLRU_Peek(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	calls := 0
+	lru.AddWithOnAccess("key1", "value1", func(key, value interface{}) {
+		calls++
+	})
+	lru.Add("key2", "value2")
+
+	val, ok := lru.Peek("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+	assert.Equal(t, 0, calls, "Peek should not invoke onAccess")
+	assert.Equal(t, "key2", lru.queue.Front().Value.(*Item).Key, "Peek should not promote")
+
+	_, ok = lru.Peek("unknown")
+	assert.False(t, ok)
+}
+
+// Тест: увеличение ёмкости не вытесняет элементы
+func TestLRU_Resize_Grow(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+
+	evicted := lru.Resize(4)
+	assert.Equal(t, 0, evicted, "Growing should not evict")
+
+	lru.Add("key3", "value3")
+	lru.Add("key4", "value4")
+	assert.Equal(t, 4, lru.Len(), "Cache should hold up to the new capacity")
+}
+
+// Тест: уменьшение ёмкости вытесняет элементы с конца очереди
+func TestLRU_Resize_Shrink(t *testing.T) {
+	lru := NewLRUCache(4).(*LRU)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Add("d", 4)
+	lru.Get("a") // очередь: a -> d -> c -> b
+
+	evicted := lru.Resize(2)
+	assert.Equal(t, 2, evicted, "Two entries should be evicted")
+	assert.Equal(t, 2, lru.Len())
+
+	_, okB := lru.Peek("b")
+	_, okC := lru.Peek("c")
+	assert.False(t, okB, "b should be evicted")
+	assert.False(t, okC, "c should be evicted")
+
+	_, okA := lru.Peek("a")
+	_, okD := lru.Peek("d")
+	assert.True(t, okA, "a should remain")
+	assert.True(t, okD, "d should remain")
+}
+
+// Тест: отрицательная ёмкость отклоняется так же, как в конструкторе
+func TestLRU_Resize_Negative(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+
+	assert.Panics(t, func() {
+		lru.Resize(-1)
+	}, "Expected panic for negative capacity")
+}
+
+// Тест: возраст элемента растёт вместе с подменённым временем и сбрасывается при обновлении
+func TestLRU_GetWithAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lru := NewLRUCache(2, WithClock(func() time.Time { return now })).(*LRU)
+	lru.Add("key1", "value1")
+
+	val, age, ok := lru.GetWithAge("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+	assert.Equal(t, time.Duration(0), age)
+
+	now = now.Add(5 * time.Second)
+	_, age, _ = lru.GetWithAge("key1")
+	assert.Equal(t, 5*time.Second, age, "Age should follow the injected clock")
+
+	now = now.Add(10 * time.Second)
+	_, age, _ = lru.GetWithAge("key1")
+	assert.Equal(t, 15*time.Second, age, "Get should not reset the age")
+
+	lru.Add("key1", "value2")
+	now = now.Add(time.Second)
+	val, age, _ = lru.GetWithAge("key1")
+	assert.Equal(t, "value2", val)
+	assert.Equal(t, time.Second, age, "Updating the value should reset the age")
+
+	_, age, ok = lru.GetWithAge("unknown")
+	assert.False(t, ok)
+	assert.Equal(t, time.Duration(0), age)
+}
+
+// Тест: статистика попаданий и промахов
+func TestLRU_Stats(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	assert.Equal(t, 0.0, lru.HitRatio(), "Ratio should be 0 without lookups")
+
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+	lru.Get("key1")    // hit
+	lru.Get("key2")    // hit
+	lru.Get("key1")    // hit
+	lru.Get("unknown") // miss
+	lru.Peek("key1")   // не учитывается
+
+	lru.Add("key3", "value3") // вытесняется key2
+	lru.Get("key2")           // miss
+
+	hits, misses := lru.Stats()
+	assert.Equal(t, uint64(3), hits)
+	assert.Equal(t, uint64(2), misses)
+	assert.InDelta(t, 0.6, lru.HitRatio(), 1e-9)
+}
+
+// Тест: GetOrAdd возвращает существующее значение и повышает его приоритет
+func TestLRU_GetOrAdd_Present(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+
+	actual, loaded := lru.GetOrAdd("key1", "other")
+	assert.True(t, loaded, "Existing key should be loaded")
+	assert.Equal(t, "value1", actual, "Existing value should not be replaced")
+	assert.Equal(t, "key1", lru.queue.Front().Value.(*Item).Key, "key1 should be promoted")
+}
+
+// Тест: GetOrAdd добавляет отсутствующее значение
+func TestLRU_GetOrAdd_Absent(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+
+	actual, loaded := lru.GetOrAdd("key1", "value1")
+	assert.False(t, loaded, "Absent key should be stored")
+	assert.Equal(t, "value1", actual)
+
+	val, ok := lru.Peek("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+}
+
+// Тест: Touch меняет порядок вытеснения, не учитываясь как Get
+func TestLRU_Touch(t *testing.T) {
+	lru := NewLRU(2)
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+
+	assert.True(t, lru.Touch("key1"))
+	assert.False(t, lru.Touch("absent"))
+	lru.Add("key3", "value3")
+
+	_, ok := lru.Peek("key1")
+	assert.True(t, ok, "Touched key should survive")
+	_, ok = lru.Peek("key2")
+	assert.False(t, ok, "Untouched key should be evicted")
+	hits, misses := lru.Stats()
+	assert.Equal(t, uint64(0), hits+misses, "Touch should not count as a Get")
+}
+
+// Тест: Replace обновляет только существующий ключ
+func TestLRU_Replace(t *testing.T) {
+	lru := NewLRU(2)
+	lru.Add("key1", "value1")
+	lru.Add("key2", "value2")
+
+	assert.True(t, lru.Replace("key1", "new"))
+	val, _ := lru.Peek("key1")
+	assert.Equal(t, "new", val)
+	assert.Equal(t, "key1", lru.queue.Front().Value.(*Item).Key, "key1 should be promoted")
+
+	assert.False(t, lru.Replace("absent", "value"), "Absent key should be rejected")
+	_, ok := lru.Peek("absent")
+	assert.False(t, ok)
+	assert.Equal(t, 2, lru.Len())
+}
+
+// Тест: AddIfAbsent не меняет существующий элемент и не повышает его приоритет
+func TestLRU_AddIfAbsent(t *testing.T) {
+	lru := NewLRU(2)
+
+	actual, loaded := lru.AddIfAbsent("key1", "value1")
+	assert.False(t, loaded, "Absent key should be stored")
+	assert.Equal(t, "value1", actual)
+	lru.Add("key2", "value2")
+
+	actual, loaded = lru.AddIfAbsent("key1", "other")
+	assert.True(t, loaded, "Existing key should be loaded")
+	assert.Equal(t, "value1", actual, "Existing value should not be replaced")
+	assert.Equal(t, "key2", lru.queue.Front().Value.(*Item).Key, "key1 should not be promoted")
+	hits, misses := lru.Stats()
+	assert.Equal(t, uint64(0), hits+misses, "AddIfAbsent should not count as an access")
+}
+
+// Тест: при конкурентных вызовах GetOrAdd побеждает ровно одно значение
+func TestLRU_GetOrAdd_Concurrent(t *testing.T) {
+	lru := NewLRUCache(4).(*LRU)
+
+	const workers = 32
+	var wg sync.WaitGroup
+	results := make([]interface{}, workers)
+	stored := make([]bool, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			actual, loaded := lru.GetOrAdd("key", i)
+			results[i] = actual
+			stored[i] = !loaded
+		}(i)
+	}
+	wg.Wait()
+
+	winners := 0
+	for i := 0; i < workers; i++ {
+		if stored[i] {
+			winners++
+		}
+		assert.Equal(t, results[0], results[i], "All callers should observe the same value")
+	}
+	assert.Equal(t, 1, winners, "Exactly one caller should store its value")
+}
+
+// Тест: GetOrCompute не вызывает загрузчик при попадании
+func TestLRU_GetOrCompute_Hit(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	lru.Add("key1", "value1")
+
+	val, err := lru.GetOrCompute("key1", func() (interface{}, error) {
+		t.Fatal("loader should not be called on hit")
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "value1", val)
+}
+
+// Тест: GetOrCompute загружает и сохраняет значение при промахе
+func TestLRU_GetOrCompute_MissLoadSuccess(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	calls := 0
+	loader := func() (interface{}, error) {
+		calls++
+		return "loaded", nil
+	}
+
+	val, err := lru.GetOrCompute("key1", loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "loaded", val)
+
+	val, err = lru.GetOrCompute("key1", loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "loaded", val)
+	assert.Equal(t, 1, calls, "Loaded value should be cached")
+}
+
+// Тест: ошибка загрузчика пробрасывается, значение не кешируется
+func TestLRU_GetOrCompute_MissLoadError(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	loadErr := errors.New("backend unavailable")
+
+	val, err := lru.GetOrCompute("key1", func() (interface{}, error) {
+		return "partial", loadErr
+	})
+	assert.ErrorIs(t, err, loadErr)
+	assert.Nil(t, val)
+
+	_, ok := lru.Peek("key1")
+	assert.False(t, ok, "Nothing should be cached on loader error")
+}
+
+// Тест: закешированный nil отличается от промаха, и loader для него больше не вызывается
+func TestLRU_GetOrCompute_CachesNil(t *testing.T) {
+	lru := NewLRU(2)
+	calls := 0
+	loader := func() (interface{}, error) {
+		calls++
+		return nil, nil
+	}
+
+	val, err := lru.GetOrCompute("key1", loader)
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+
+	val, ok := lru.Get("key1")
+	assert.True(t, ok, "Cached nil should be a hit")
+	assert.Nil(t, val)
+
+	val, err = lru.GetOrCompute("key1", loader)
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+	assert.Equal(t, 1, calls, "Cached nil should not be loaded again")
+}
+
+// Тест: Keys возвращает ключи в порядке недавнего использования
+func TestLRU_Keys(t *testing.T) {
+	lru := NewLRUCache(3).(*LRU)
+	assert.Empty(t, lru.Keys(), "Empty cache should have no keys")
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Get("a")    // a -> c -> b
+	lru.Add("d", 4) // вытесняется b: d -> a -> c
+	lru.Get("c")    // c -> d -> a
+
+	keys := lru.Keys()
+	assert.Equal(t, []interface{}{"c", "d", "a"}, keys)
+
+	keys[0] = "mutated"
+	assert.Equal(t, []interface{}{"c", "d", "a"}, lru.Keys(), "Keys should return a snapshot copy")
+}
+
+// Тест: Range обходит все элементы без изменения порядка
+func TestLRU_Range(t *testing.T) {
+	lru := NewLRUCache(3).(*LRU)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	var keys, values []interface{}
+	lru.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	})
+	assert.Equal(t, []interface{}{"c", "b", "a"}, keys)
+	assert.Equal(t, []interface{}{3, 2, 1}, values)
+	assert.Equal(t, []interface{}{"c", "b", "a"}, lru.Keys(), "Range should not change recency")
+
+	hits, misses := lru.Stats()
+	assert.Zero(t, hits+misses, "Range should not affect stats")
+}
+
+// Тест: Range останавливается, когда f возвращает false
+func TestLRU_Range_EarlyStop(t *testing.T) {
+	lru := NewLRUCache(3).(*LRU)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	visited := 0
+	lru.Range(func(key, value interface{}) bool {
+		visited++
+		return visited < 2
+	})
+	assert.Equal(t, 2, visited, "Iteration should stop after f returns false")
+}
+
+// Тест: элементы принимаются, пока суммарная стоимость в пределах лимита
+func TestLRU_AddWithCost_Admission(t *testing.T) {
+	lru := NewLRUCacheWithMaxCost(10).(*LRU)
+
+	assert.True(t, lru.AddWithCost("a", "A", 4))
+	assert.True(t, lru.AddWithCost("b", "B", 6))
+	assert.Equal(t, int64(10), lru.TotalCost())
+	assert.Equal(t, 2, lru.Len(), "Both items fit exactly into the budget")
+
+	assert.True(t, lru.Add("c", "C"), "Add should use cost 1")
+	assert.Equal(t, int64(7), lru.TotalCost(), "a should be evicted to fit c")
+	_, ok := lru.Peek("a")
+	assert.False(t, ok)
+}
+
+// Тест: крупный элемент вытесняет сразу несколько мелких
+func TestLRU_AddWithCost_MultiEviction(t *testing.T) {
+	lru := NewLRUCacheWithMaxCost(10).(*LRU)
+	lru.AddWithCost("a", "A", 3)
+	lru.AddWithCost("b", "B", 3)
+	lru.AddWithCost("c", "C", 3)
+	lru.Get("a") // a -> c -> b
+
+	assert.True(t, lru.AddWithCost("big", "BIG", 7))
+	assert.Equal(t, []interface{}{"big", "a"}, lru.Keys(), "b and c should be evicted from the back")
+	assert.Equal(t, int64(10), lru.TotalCost())
+
+	// Увеличение стоимости существующего элемента тоже вытесняет
+	assert.True(t, lru.AddWithCost("a", "A2", 5))
+	assert.Equal(t, []interface{}{"a"}, lru.Keys())
+	assert.Equal(t, int64(5), lru.TotalCost())
+}
+
+// Тест: результат AddWithCost означает, что запись принята, и отличает обновление от отказа
+func TestLRU_AddWithCost_UpdateVsReject(t *testing.T) {
+	lru := NewLRUCacheWithMaxCost(10).(*LRU)
+	assert.True(t, lru.AddWithCost("a", "A", 5), "New key should be accepted")
+
+	assert.True(t, lru.AddWithCost("a", "A2", 6), "Update should be accepted")
+	val, _ := lru.Peek("a")
+	assert.Equal(t, "A2", val)
+	assert.Equal(t, int64(6), lru.TotalCost())
+
+	assert.False(t, lru.AddWithCost("a", "A3", 11), "Oversized update should be rejected")
+	val, _ = lru.Peek("a")
+	assert.Equal(t, "A2", val)
+	assert.Equal(t, int64(6), lru.TotalCost())
+}
+
+// Тест: элемент с отрицательной стоимостью отклоняется без изменения кеша
+func TestLRU_AddWithCost_RejectNegative(t *testing.T) {
+	lru := NewLRUCacheWithMaxCost(10).(*LRU)
+	lru.AddWithCost("a", "A", 5)
+
+	assert.False(t, lru.AddWithCost("b", "B", -100), "Negative cost should be rejected")
+	assert.False(t, lru.AddWithCost("a", "A2", -1), "Negative cost update should be rejected")
+
+	val, _ := lru.Peek("a")
+	assert.Equal(t, "A", val)
+	assert.Equal(t, 1, lru.Len())
+	assert.Equal(t, int64(5), lru.TotalCost())
+}
+
+// Тест: элемент дороже лимита отклоняется без изменения кеша
+func TestLRU_AddWithCost_RejectOversized(t *testing.T) {
+	lru := NewLRUCacheWithMaxCost(10).(*LRU)
+	lru.AddWithCost("a", "A", 5)
+
+	assert.False(t, lru.AddWithCost("huge", "HUGE", 11), "Oversized item should be rejected")
+	assert.False(t, lru.AddWithCost("a", "A2", 11), "Oversized update should be rejected")
+
+	val, ok := lru.Peek("a")
+	assert.True(t, ok, "Existing entries should be untouched")
+	assert.Equal(t, "A", val)
+	assert.Equal(t, int64(5), lru.TotalCost())
+
+	lru.Remove("a")
+	assert.Equal(t, int64(0), lru.TotalCost(), "Remove should release cost")
+}
+
+// Тест: снимок и восстановление сохраняют содержимое и порядок
+func TestLRU_SnapshotRestore(t *testing.T) {
+	src := NewLRUCache(3).(*LRU)
+	src.Add("a", "1")
+	src.Add("b", 2.5)
+	src.Add("c", true)
+	src.Get("a") // a -> c -> b
+
+	data, err := src.Snapshot()
+	assert.NoError(t, err)
+
+	dst := NewLRUCache(3).(*LRU)
+	dst.Add("stale", "x")
+	assert.NoError(t, dst.Restore(data))
+
+	assert.Equal(t, []interface{}{"a", "c", "b"}, dst.Keys(), "Recency order should survive")
+	val, _ := dst.Peek("a")
+	assert.Equal(t, "1", val)
+	val, _ = dst.Peek("b")
+	assert.Equal(t, 2.5, val)
+	val, _ = dst.Peek("c")
+	assert.Equal(t, true, val)
+	_, ok := dst.Peek("stale")
+	assert.False(t, ok, "Restore should replace previous contents")
+
+	// После восстановления вытесняется наименее недавний элемент
+	dst.Add("d", "4")
+	_, ok = dst.Peek("b")
+	assert.False(t, ok)
+}
+
+// Тест: при восстановлении в меньший кеш остаются самые недавние элементы
+func TestLRU_Restore_SmallerCapacity(t *testing.T) {
+	src := NewLRUCache(3).(*LRU)
+	src.Add("a", 1)
+	src.Add("b", 2)
+	src.Add("c", 3)
+	data, _ := src.Snapshot()
+
+	dst := NewLRUCache(2).(*LRU)
+	assert.NoError(t, dst.Restore(data))
+	assert.Equal(t, []interface{}{"c", "b"}, dst.Keys())
+}
+
+// Тест: Restore в замороженный кеш восстанавливает элементы, а кеш остаётся замороженным
+func TestLRU_Restore_Frozen(t *testing.T) {
+	src := NewLRU(3)
+	src.Add("a", 1)
+	src.Add("b", 2)
+	data, _ := src.Snapshot()
+
+	dst := NewLRU(3)
+	dst.Add("old", 0)
+	dst.Freeze()
+	assert.NoError(t, dst.Restore(data))
+	assert.Equal(t, []interface{}{"b", "a"}, dst.Keys())
+	assert.True(t, dst.Frozen())
+	assert.False(t, dst.Add("c", 3), "Cache should stay frozen after Restore")
+}
+
+// Тест: ошибки кодирования и разбора возвращаются вызывающему
+func TestLRU_Snapshot_Errors(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	lru.Add("fn", func() {})
+
+	_, err := lru.Snapshot()
+	assert.Error(t, err, "Non JSON-encodable value should fail")
+
+	assert.Error(t, lru.Restore([]byte("not json")))
+	_, ok := lru.Peek("fn")
+	assert.True(t, ok, "Failed restore should keep current contents")
+}
+
+// Тест: одновременные промахи по одному ключу вызывают загрузчик ровно один раз
+func TestLRU_GetOrCompute_SingleFlight(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+
+	const workers = 50
+	var calls int32
+	release := make(chan struct{})
+	loader := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "loaded", nil
+	}
+
+	var started, done sync.WaitGroup
+	results := make([]interface{}, workers)
+	for i := 0; i < workers; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			results[i], _ = lru.GetOrCompute("key", loader)
+		}(i)
+	}
+	started.Wait()
+	time.Sleep(10 * time.Millisecond) // даём горутинам дойти до ожидания загрузки
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "Loader should run exactly once")
+	for i := 0; i < workers; i++ {
+		assert.Equal(t, "loaded", results[i])
+	}
+	assert.Empty(t, lru.inflight, "In-flight entry should be cleaned up")
+}
+
+// Тест: из множества одновременных вызывающих computedByUs получает ровно один - тот, чей загрузчик выполнился
+func TestLRU_GetOrComputeOwned_ExactlyOneComputes(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+
+	const workers = 50
+	release := make(chan struct{})
+	loader := func() (interface{}, error) {
+		<-release
+		return "loaded", nil
+	}
+
+	var started, done sync.WaitGroup
+	var owners int32
+	results := make([]interface{}, workers)
+	for i := 0; i < workers; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			value, computedByUs, err := lru.GetOrComputeOwned("key", loader)
+			assert.NoError(t, err)
+			if computedByUs {
+				atomic.AddInt32(&owners, 1)
+			}
+			results[i] = value
+		}(i)
+	}
+	started.Wait()
+	time.Sleep(10 * time.Millisecond) // даём горутинам дойти до ожидания загрузки
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&owners), "Exactly one caller should report computedByUs")
+	for i := 0; i < workers; i++ {
+		assert.Equal(t, "loaded", results[i])
+	}
+
+	value, computedByUs, err := lru.GetOrComputeOwned("key", loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.False(t, computedByUs, "Cache hit should not report computedByUs")
+}
+
+// Тест: ошибка загрузчика получают все ожидающие
+func TestLRU_GetOrCompute_SingleFlightError(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	loadErr := errors.New("backend unavailable")
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = lru.GetOrCompute("key", func() (interface{}, error) {
+				<-release
+				return nil, loadErr
+			})
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.ErrorIs(t, err, loadErr)
+	}
+	_, ok := lru.Peek("key")
+	assert.False(t, ok)
+}
+
+// Тест: panic загрузчика доходит до всех ожидающих и не блокирует следующие вызовы для ключа
+func TestLRU_GetOrCompute_LoaderPanic(t *testing.T) {
+	lru := NewLRU(2)
+	started := make(chan struct{})
+
+	waiterPanic := make(chan interface{}, 1)
+	go func() {
+		<-started
+		defer func() { waiterPanic <- recover() }()
+		lru.GetOrCompute("key", func() (interface{}, error) {
+			return "unused", nil
+		})
+	}()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		lru.GetOrCompute("key", func() (interface{}, error) {
+			close(started)
+			time.Sleep(20 * time.Millisecond) // даём второму вызову дождаться загрузки
+			panic("boom")
+		})
+	})
+
+	select {
+	case r := <-waiterPanic:
+		// Ожидающий либо получил panic загрузки, либо пришёл после неё и загрузил значение сам
+		if r != nil {
+			assert.Equal(t, "boom", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Waiter should not block after loader panic")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		val, err := lru.GetOrCompute("key", func() (interface{}, error) {
+			return "loaded", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "loaded", val)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("GetOrCompute should not block after loader panic")
+	}
+}
+
+// Тест: PeekOldest и PeekNewest возвращают концы очереди без изменения порядка
+func TestLRU_PeekOldestNewest(t *testing.T) {
+	lru := NewLRUCache(3).(*LRU)
+	_, _, ok := lru.PeekOldest()
+	assert.False(t, ok, "Empty cache has no oldest entry")
+	_, _, ok = lru.PeekNewest()
+	assert.False(t, ok, "Empty cache has no newest entry")
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Get("a") // a -> c -> b
+
+	key, value, ok := lru.PeekOldest()
+	assert.True(t, ok)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, 2, value)
+
+	key, value, ok = lru.PeekNewest()
+	assert.True(t, ok)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, 1, value)
+
+	assert.Equal(t, []interface{}{"a", "c", "b"}, lru.Keys(), "Peeks should not promote")
+
+	lru.Add("d", 4) // вытесняется b, как и предсказывал PeekOldest
+	key, _, _ = lru.PeekOldest()
+	assert.Equal(t, "c", key)
+	key, _, _ = lru.PeekNewest()
+	assert.Equal(t, "d", key)
+}
+
+// Тест: PutAll вытесняет самые старые элементы, GetAll возвращает только попадания и повышает их приоритет
+func TestLRU_PutAllGetAll(t *testing.T) {
+	lru := NewLRUCache(3).(*LRU)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	lru.PutAll(map[interface{}]interface{}{"d": 4, "e": 5})
+	assert.Equal(t, 3, lru.Len())
+	_, ok := lru.Peek("a")
+	assert.False(t, ok)
+	_, ok = lru.Peek("b")
+	assert.False(t, ok)
+
+	hits, misses := lru.Stats()
+	got := lru.GetAll([]interface{}{"c", "a", "missing"})
+	assert.Equal(t, map[interface{}]interface{}{"c": 3}, got)
+	newHits, newMisses := lru.Stats()
+	assert.Equal(t, hits+1, newHits)
+	assert.Equal(t, misses+2, newMisses)
+
+	key, _, _ := lru.PeekNewest()
+	assert.Equal(t, "c", key, "GetAll should promote found keys")
+}
+
+// Тест: RemoveOldest и EvictN вытесняют элементы с конца очереди
+func TestLRU_RemoveOldestEvictN(t *testing.T) {
+	lru := NewLRUCache(5).(*LRU)
+	_, _, ok := lru.RemoveOldest()
+	assert.False(t, ok, "Empty cache has nothing to remove")
+	assert.Equal(t, 0, lru.EvictN(3), "Empty cache has nothing to evict")
+
+	for i, key := range []string{"a", "b", "c", "d"} {
+		lru.Add(key, i)
+	}
+	lru.Get("a") // a -> d -> c -> b
+
+	key, value, ok := lru.RemoveOldest()
+	assert.True(t, ok)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, 1, value)
+
+	assert.Equal(t, 1, lru.EvictN(1))
+	assert.Equal(t, []interface{}{"a", "d"}, lru.Keys())
+
+	assert.Equal(t, 2, lru.EvictN(10), "EvictN should stop when the cache is empty")
+	assert.Equal(t, 0, lru.Len())
+}
+
+// Тест: String выводит элементы от начала очереди к концу и не меняет порядок
+func TestLRU_String(t *testing.T) {
+	lru := NewLRUCache(3).(*LRU)
+	assert.Equal(t, "[]", lru.String())
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Get("a")
+
+	assert.Equal(t, "[a=1, c=3, b=2]", lru.String())
+	assert.Equal(t, "[a=1, c=3, b=2]", lru.String(), "String should not promote entries")
+}
+
+// Тест: Evictions считает вытеснения по ёмкости и через EvictN, но не явные удаления
+func TestLRU_Evictions(t *testing.T) {
+	lru := NewLRUCache(2).(*LRU)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	assert.Equal(t, uint64(1), lru.Evictions())
+
+	lru.Remove("b")
+	assert.Equal(t, uint64(1), lru.Evictions(), "Remove is not an eviction")
+
+	lru.EvictN(1)
+	assert.Equal(t, uint64(2), lru.Evictions())
+}
+
+// Тест: NewLRU возвращает конкретный тип, NewLRUCache - тот же кеш за интерфейсом
+func TestLRU_NewLRU(t *testing.T) {
+	lru := NewLRU(2)
+	lru.Add("a", 1)
+	value, ok := lru.Peek("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	assert.IsType(t, lru, NewLRUCache(2))
+	assert.Panics(t, func() { NewLRU(-1) })
+}
+
+// Тест: Values возвращает значения в порядке Keys и не повышает приоритет
+func TestLRU_Values(t *testing.T) {
+	lru := NewLRU(3)
+	assert.Empty(t, lru.Values())
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Get("a")
+
+	assert.Equal(t, []interface{}{1, 3, 2}, lru.Values())
+	assert.Equal(t, []interface{}{"a", "c", "b"}, lru.Keys(), "Values should not promote entries")
+}
+
+// Тест: ToMap и NewLRUFromMap переносят элементы без изменений
+func TestLRU_ToMapRoundTrip(t *testing.T) {
+	lru := NewLRU(3)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	m := lru.ToMap()
+	assert.Equal(t, map[interface{}]interface{}{"a": 1, "b": 2, "c": 3}, m)
+	assert.Equal(t, []interface{}{"c", "b", "a"}, lru.Keys(), "ToMap should not promote entries")
+	hits, misses := lru.Stats()
+	assert.Equal(t, uint64(0), hits+misses)
+
+	restored := NewLRUFromMap(3, m)
+	assert.Equal(t, m, restored.ToMap())
+
+	overflow := NewLRUFromMap(2, m)
+	assert.Equal(t, 2, overflow.Len(), "Overflow should evict down to capacity")
+}
+
+// Тест: GetWithContext кеширует успешно загруженное значение
+func TestLRU_GetWithContext_Success(t *testing.T) {
+	lru := NewLRU(2)
+	calls := 0
+	loader := func(ctx context.Context) (interface{}, error) {
+		calls++
+		return "value", nil
+	}
+
+	value, err := lru.GetWithContext(context.Background(), "key", loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	value, err = lru.GetWithContext(context.Background(), "key", loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+	assert.Equal(t, 1, calls, "Second call should be served from cache")
+}
+
+// Тест: отмена контекста прерывает ожидание загрузки, и ничего не кешируется
+func TestLRU_GetWithContext_Cancelled(t *testing.T) {
+	lru := NewLRU(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	value, err := lru.GetWithContext(ctx, "key", func(ctx context.Context) (interface{}, error) {
+		defer close(finished)
+		close(started)
+		<-release
+		return "late", nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, value)
+
+	close(release)
+	<-finished
+	_, ok := lru.Peek("key")
+	assert.False(t, ok, "Value loaded after cancellation should not be cached")
+
+	_, err = lru.GetWithContext(ctx, "key", func(ctx context.Context) (interface{}, error) {
+		t.Fatal("Loader should not run for an already cancelled context")
+		return nil, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// Тест: истечение срока контекста возвращает DeadlineExceeded, ошибка загрузчика пробрасывается как есть
+func TestLRU_GetWithContext_DeadlineAndError(t *testing.T) {
+	lru := NewLRU(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := lru.GetWithContext(ctx, "slow", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	loadErr := errors.New("load failed")
+	_, err = lru.GetWithContext(context.Background(), "bad", func(ctx context.Context) (interface{}, error) {
+		return nil, loadErr
+	})
+	assert.ErrorIs(t, err, loadErr)
+	assert.Equal(t, 0, lru.Len())
+}
+
+// Тест: GetMultiple разделяет попадания и промахи, сохраняя порядок промахов
+func TestLRU_GetMultiple(t *testing.T) {
+	lru := NewLRU(3)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+
+	values, misses := lru.GetMultiple([]interface{}{"x", "a", "y", "c", "z"})
+	assert.Equal(t, map[interface{}]interface{}{"a": 1, "c": 3}, values)
+	assert.Equal(t, []interface{}{"x", "y", "z"}, misses)
+	assert.Equal(t, []interface{}{"c", "a", "b"}, lru.Keys(), "Hits should be promoted in request order")
+
+	values, misses = lru.GetMultiple([]interface{}{"a", "b"})
+	assert.Len(t, values, 2)
+	assert.Empty(t, misses)
+}
+
+// Тест: Metadata отражает Get и Add, но не Peek
+func TestLRU_Metadata(t *testing.T) {
+	now := time.Unix(1000, 0)
+	lru := NewLRU(2, WithClock(func() time.Time { return now }))
+
+	_, _, ok := lru.Metadata("a")
+	assert.False(t, ok)
+
+	lru.Add("a", 1)
+	lastAccess, count, ok := lru.Metadata("a")
+	assert.True(t, ok)
+	assert.Equal(t, now, lastAccess)
+	assert.Equal(t, 1, count)
+
+	now = now.Add(time.Second)
+	lru.Get("a")
+	lastAccess, count, _ = lru.Metadata("a")
+	assert.Equal(t, now, lastAccess)
+	assert.Equal(t, 2, count)
+
+	now = now.Add(time.Second)
+	lru.Peek("a")
+	lastAccess, count, _ = lru.Metadata("a")
+	assert.Equal(t, now.Add(-time.Second), lastAccess, "Peek should not update the access time")
+	assert.Equal(t, 2, count, "Peek should not count as an access")
+
+	lru.Add("a", 10)
+	lastAccess, count, _ = lru.Metadata("a")
+	assert.Equal(t, now, lastAccess)
+	assert.Equal(t, 3, count)
+}
+
+// Тест: вытеснение и явное удаление порождают события с нужной причиной
+func TestLRU_EvictionEvents(t *testing.T) {
+	lru := NewLRU(2)
+	events := lru.EvictionEvents()
+
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	assert.Equal(t, cache.EvictedEntry{Key: "a", Value: 1, Reason: cache.EvictedCapacity}, <-events)
+
+	lru.Remove("b")
+	assert.Equal(t, cache.EvictedEntry{Key: "b", Value: 2, Reason: cache.EvictedManual}, <-events)
+
+	lru.EvictN(1)
+	assert.Equal(t, cache.EvictedEntry{Key: "c", Value: 3, Reason: cache.EvictedManual}, <-events)
+
+	lru.StopEvictionEvents()
+	_, open := <-events
+	assert.False(t, open, "Stopped channel should be closed")
+	lru.Add("d", 4)
+	lru.Add("e", 5)
+	lru.Add("f", 6) // без подписчиков события не отправляются
+}
+
+// Тест: заполненный буфер событий не блокирует кеш, лишние события отбрасываются
+func TestLRU_EvictionEvents_SlowConsumer(t *testing.T) {
+	lru := NewLRU(1)
+	events := lru.EvictionEvents()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < cache.EventBufferSize*2; i++ {
+			lru.Add(i, i)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Full event buffer should not block the cache")
+	}
+	assert.Equal(t, cache.EventBufferSize, len(events))
+	assert.Equal(t, 0, (<-events).Key, "The oldest events should be kept")
+}
+
+// Тест: в замороженном кеше записи игнорируются, а чтения не меняют порядок
+func TestLRU_Freeze(t *testing.T) {
+	lru := NewLRU(2)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Freeze()
+	assert.True(t, lru.Frozen())
+
+	assert.False(t, lru.Add("c", 3))
+	assert.False(t, lru.Add("a", 10))
+	assert.False(t, lru.Remove("a"))
+	_, _, ok := lru.RemoveOldest()
+	assert.False(t, ok)
+	assert.Equal(t, 0, lru.EvictN(1))
+
+	value, ok := lru.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value, "Frozen Add should not update values")
+	assert.Equal(t, []interface{}{"b", "a"}, lru.Keys(), "Frozen Get should not promote")
+	_, count, _ := lru.Metadata("a")
+	assert.Equal(t, 1, count)
+
+	computed, err := lru.GetOrCompute("c", func() (interface{}, error) { return 3, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 3, computed)
+	_, ok = lru.Peek("c")
+	assert.False(t, ok, "Loader results should not be stored while frozen")
+
+	lru.Unfreeze()
+	assert.True(t, lru.Add("c", 3))
+	assert.Equal(t, []interface{}{"c", "b"}, lru.Keys())
+}
+
+// Тест: изменения копии не затрагивают оригинал и наоборот
+func TestLRU_Clone(t *testing.T) {
+	lru := NewLRU(3)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	lru.Add("c", 3)
+	lru.Get("a")
+
+	clone := lru.Clone()
+	assert.Equal(t, lru.Keys(), clone.Keys())
+	assert.Equal(t, lru.ToMap(), clone.ToMap())
+
+	clone.Get("b")
+	clone.Add("d", 4)
+	clone.Add("a", 10)
+	assert.Equal(t, []interface{}{"a", "d", "b"}, clone.Keys())
+	assert.Equal(t, []interface{}{"a", "c", "b"}, lru.Keys(), "Source order should not change")
+	value, _ := lru.Peek("a")
+	assert.Equal(t, 1, value, "Source values should not change")
+
+	lru.Remove("b")
+	_, ok := clone.Peek("b")
+	assert.True(t, ok, "Clone should not see source removals")
+
+	hits, _ := lru.Stats()
+	cloneHits, _ := clone.Stats()
+	assert.Equal(t, uint64(1), hits)
+	assert.Equal(t, uint64(2), cloneHits, "Clone starts from the source statistics")
+}
+
+// Тест: RemovePrefix удаляет только строковые ключи с префиксом
+func TestLRU_RemovePrefix(t *testing.T) {
+	lru := NewLRU(10)
+	lru.AddWithCost("user:1:profile", "p1", 1)
+	lru.Add("user:1:settings", "s1")
+	lru.Add("user:12:profile", "p12")
+	lru.Add("user:2:profile", "p2")
+	lru.Add(1, "int key")
+	lru.Add([2]string{"user:1:", "x"}, "array key")
+
+	assert.Equal(t, 2, lru.RemovePrefix("user:1:"))
+	assert.Equal(t, 4, lru.Len())
+	_, ok := lru.Peek("user:12:profile")
+	assert.True(t, ok, "user:12: does not start with user:1:")
+	_, ok = lru.Peek(1)
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), lru.TotalCost())
+
+	assert.Equal(t, 0, lru.RemovePrefix("order:"))
+	assert.Equal(t, 2, lru.RemovePrefix(""), "Empty prefix matches every string key")
+	assert.Equal(t, 2, lru.Len())
+}
+
+// Тест: InvalidateTag удаляет ровно элементы с тегом, любое удаление чистит индекс тегов
+func TestLRU_Tags(t *testing.T) {
+	lru := NewLRU(4)
+	lru.AddWithTags("user:1", "u1", "users", "team:a")
+	lru.AddWithTags("user:2", "u2", "users", "team:b")
+	lru.AddWithTags("team:a", "ta", "team:a")
+	lru.Add("plain", "p")
+
+	assert.Equal(t, 2, lru.InvalidateTag("team:a"))
+	assert.ElementsMatch(t, []interface{}{"user:2", "plain"}, lru.Keys())
+	assert.Equal(t, 0, lru.InvalidateTag("team:a"), "Invalidated tag should be empty")
+
+	lru.AddWithTags("user:3", "u3", "users")
+	lru.Remove("user:2")
+	assert.Equal(t, 1, lru.InvalidateTag("users"), "Removed entries should leave the tag index")
+	assert.Equal(t, []interface{}{"plain"}, lru.Keys())
+
+	lru.AddWithTags("a", 1, "old")
+	lru.AddWithTags("a", 2, "new")
+	assert.Equal(t, 0, lru.InvalidateTag("old"), "Re-tagging replaces tags")
+	lru.Add("a", 3)
+	assert.Equal(t, 1, lru.InvalidateTag("new"), "Plain Add keeps tags")
+
+	small := NewLRU(1)
+	small.AddWithTags("x", 1, "t")
+	small.Add("y", 2) // x вытеснен
+	assert.Equal(t, 0, small.InvalidateTag("t"))
+	assert.Empty(t, small.tags, "Evicted entries should leave the tag index")
+}
+
+// BenchmarkLRUFillLarge измеряет заполнение большого кеша с нуля
+func BenchmarkLRUFillLarge(b *testing.B) {
+	const n = 100000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lru := NewLRU(n)
+		for j := 0; j < n; j++ {
+			lru.Add(j, j)
+		}
+	}
+}
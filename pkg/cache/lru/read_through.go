@@ -0,0 +1,149 @@
+package lru
+
+import (
+	"LRU_cache/pkg/cache"
+	"time"
+)
+
+// ReadThrough - LRU кеш поверх хранилища: промах Get загружает значение из хранилища и кеширует его,
+// а в режиме write-through Add сначала сохраняет значение в хранилище
+type ReadThrough struct {
+	lru          *LRU
+	store        cache.Store
+	writeThrough bool
+
+	negativeTTL time.Duration
+	negatives   *LRU             // ключ -> момент истечения отметки об отсутствии в хранилище
+	now         func() time.Time // источник текущего времени
+}
+
+// ReadThroughOption настраивает ReadThrough при создании
+type ReadThroughOption func(*ReadThrough)
+
+// WithWriteThrough включает запись в хранилище при каждом Add
+func WithWriteThrough() ReadThroughOption {
+	return func(r *ReadThrough) {
+		r.writeThrough = true
+	}
+}
+
+// WithNegativeTTL включает запоминание промахов: если хранилище сообщает об отсутствии ключа,
+// следующие Get в течение ttl возвращают промах без обращения к хранилищу.
+// Отметок хранится не больше, чем ёмкость кеша
+func WithNegativeTTL(ttl time.Duration) ReadThroughOption {
+	return func(r *ReadThrough) {
+		r.negativeTTL = ttl
+	}
+}
+
+// NewLRUReadThrough создает read-through кеш ёмкостью capacity поверх store
+func NewLRUReadThrough(capacity int, store cache.Store, opts ...ReadThroughOption) *ReadThrough {
+	r := &ReadThrough{
+		lru:   NewLRUCache(capacity).(*LRU),
+		store: store,
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.negativeTTL > 0 {
+		r.negatives = NewLRU(capacity)
+	}
+	return r
+}
+
+// Fetch возвращает значение из кеша, а при промахе загружает его из хранилища и кеширует.
+// Ошибка хранилища возвращается вызывающему, в кеш при этом ничего не попадает.
+// Найденное в хранилище значение nil кешируется и отличается от отсутствия ключа
+func (r *ReadThrough) Fetch(key interface{}) (value interface{}, ok bool, err error) {
+	if value, ok := r.lru.Get(key); ok {
+		return value, true, nil
+	}
+	if r.knownMissing(key) {
+		return nil, false, nil
+	}
+
+	value, found, err := r.store.Load(key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		if r.negatives != nil {
+			r.negatives.Add(key, r.now().Add(r.negativeTTL))
+		}
+		return nil, false, nil
+	}
+	r.lru.Add(key, value)
+	return value, true, nil
+}
+
+// Get работает как Fetch, ошибки хранилища считаются промахом
+func (r *ReadThrough) Get(key interface{}) (value interface{}, ok bool) {
+	value, ok, _ = r.Fetch(key)
+	return value, ok
+}
+
+// Set кеширует значение, а в режиме write-through предварительно сохраняет его в хранилище.
+// Если сохранение не удалось, кеш не меняется и возвращается ошибка
+func (r *ReadThrough) Set(key, value interface{}) error {
+	if r.writeThrough {
+		if err := r.store.Save(key, value); err != nil {
+			return err
+		}
+	}
+	r.forgetMissing(key)
+	r.lru.Add(key, value)
+	return nil
+}
+
+// Add работает как Set по правилам cache.Cache: true только для нового ключа, false для существующего
+// ключа или при ошибке записи в хранилище
+func (r *ReadThrough) Add(key, value interface{}) bool {
+	_, existed := r.lru.Peek(key)
+	if err := r.Set(key, value); err != nil {
+		return false
+	}
+	return !existed
+}
+
+// Remove удаляет значение и отметку об отсутствии только из кеша, хранилище не меняется
+func (r *ReadThrough) Remove(key interface{}) (ok bool) {
+	r.forgetMissing(key)
+	return r.lru.Remove(key)
+}
+
+// Len возвращает количество закешированных значений
+func (r *ReadThrough) Len() int {
+	return r.lru.Len()
+}
+
+// Clear очищает только кеш, хранилище не меняется
+func (r *ReadThrough) Clear() {
+	r.lru.Clear()
+	if r.negatives != nil {
+		r.negatives.Clear()
+	}
+}
+
+// knownMissing сообщает, что ключ недавно не нашёлся в хранилище. Истёкшая отметка удаляется
+func (r *ReadThrough) knownMissing(key interface{}) bool {
+	if r.negatives == nil {
+		return false
+	}
+	expires, ok := r.negatives.Get(key)
+	if !ok {
+		return false
+	}
+	if r.now().Before(expires.(time.Time)) {
+		return true
+	}
+	r.negatives.Remove(key)
+	return false
+}
+
+// forgetMissing удаляет отметку об отсутствии ключа
+func (r *ReadThrough) forgetMissing(key interface{}) {
+	if r.negatives != nil {
+		r.negatives.Remove(key)
+	}
+}
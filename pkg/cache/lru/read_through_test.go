@@ -0,0 +1,182 @@
+package lru
+
+import (
+	"LRU_cache/pkg/cache"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStore - хранилище в памяти со счётчиками обращений
+type fakeStore struct {
+	data    map[interface{}]interface{}
+	loads   int
+	saves   int
+	loadErr error
+	saveErr error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{data: map[interface{}]interface{}{}}
+}
+
+func (s *fakeStore) Load(key interface{}) (interface{}, bool, error) {
+	s.loads++
+	if s.loadErr != nil {
+		return nil, false, s.loadErr
+	}
+	value, found := s.data[key]
+	return value, found, nil
+}
+
+func (s *fakeStore) Save(key, value interface{}) error {
+	s.saves++
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.data[key] = value
+	return nil
+}
+
+var _ cache.Cache = (*ReadThrough)(nil)
+
+// Тест: промах загружает значение из хранилища и кеширует его
+func TestReadThrough_MissLoadsAndCaches(t *testing.T) {
+	store := newFakeStore()
+	store.data["key1"] = "value1"
+	r := NewLRUReadThrough(2, store)
+
+	val, ok := r.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+	assert.Equal(t, 1, store.loads)
+
+	val, ok = r.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+	assert.Equal(t, 1, store.loads, "Second Get should be served from cache")
+
+	_, ok = r.Get("absent")
+	assert.False(t, ok, "Key missing in store should be a miss")
+}
+
+// Тест: ошибка хранилища пробрасывается и ничего не кешируется
+func TestReadThrough_LoadError(t *testing.T) {
+	store := newFakeStore()
+	store.loadErr = errors.New("store down")
+	r := NewLRUReadThrough(2, store)
+
+	_, ok, err := r.Fetch("key1")
+	assert.ErrorIs(t, err, store.loadErr)
+	assert.False(t, ok)
+	assert.Equal(t, 0, r.lru.Len())
+}
+
+// Тест: в режиме write-through Add сохраняет значение в хранилище
+func TestReadThrough_WriteThrough(t *testing.T) {
+	store := newFakeStore()
+	r := NewLRUReadThrough(2, store, WithWriteThrough())
+
+	assert.True(t, r.Add("key1", "value1"))
+	assert.Equal(t, "value1", store.data["key1"], "Value should be written to store")
+	assert.Equal(t, 1, store.saves)
+
+	val, ok := r.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+	assert.Equal(t, 0, store.loads, "Written value should be cached")
+
+	store.saveErr = errors.New("read-only")
+	assert.Error(t, r.Set("key2", "value2"))
+	_, ok = r.lru.Peek("key2")
+	assert.False(t, ok, "Failed write should not be cached")
+}
+
+// Тест: без write-through Add не трогает хранилище, Remove удаляет только из кеша
+func TestReadThrough_CacheOnly(t *testing.T) {
+	store := newFakeStore()
+	store.data["key1"] = "stored"
+	r := NewLRUReadThrough(2, store)
+
+	r.Add("key1", "cached")
+	assert.Equal(t, 0, store.saves)
+	assert.Equal(t, "stored", store.data["key1"])
+
+	assert.True(t, r.Remove("key1"))
+	val, _ := r.Get("key1")
+	assert.Equal(t, "stored", val, "After Remove the value should be reloaded from store")
+}
+
+// Тест: Clear очищает только кеш, следующий Get снова загружает значение из хранилища
+func TestReadThrough_Clear(t *testing.T) {
+	store := newFakeStore()
+	r := NewLRUReadThrough(2, store, WithWriteThrough())
+	r.Add("key1", "value1")
+	assert.Equal(t, 1, r.Len())
+
+	r.Clear()
+	assert.Equal(t, 0, r.Len())
+	assert.Equal(t, "value1", store.data["key1"], "Store should keep the value")
+
+	val, ok := r.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+	assert.Equal(t, 1, store.loads)
+}
+
+// Тест: в течение negative TTL повторные промахи не обращаются к хранилищу
+func TestReadThrough_NegativeTTL(t *testing.T) {
+	store := newFakeStore()
+	r := NewLRUReadThrough(2, store, WithNegativeTTL(time.Minute))
+	now := time.Unix(1000, 0)
+	r.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, ok := r.Get("absent")
+		assert.False(t, ok)
+	}
+	assert.Equal(t, 1, store.loads, "Repeated misses should call the store once")
+	assert.Equal(t, 0, r.Len(), "Tombstones should not count as cached values")
+
+	store.data["absent"] = "appeared"
+	now = now.Add(time.Minute)
+	val, ok := r.Get("absent")
+	assert.True(t, ok, "Expired tombstone should allow a reload")
+	assert.Equal(t, "appeared", val)
+	assert.Equal(t, 2, store.loads)
+}
+
+// Тест: Add снимает отметку об отсутствии ключа
+func TestReadThrough_NegativeTTL_AddClearsTombstone(t *testing.T) {
+	store := newFakeStore()
+	r := NewLRUReadThrough(2, store, WithNegativeTTL(time.Minute))
+
+	_, ok := r.Get("key1")
+	assert.False(t, ok)
+	assert.True(t, r.Add("key1", "value1"))
+	r.lru.Remove("key1") // значение вытеснено из кеша, но есть в хранилище
+	store.data["key1"] = "stored"
+
+	val, ok := r.Get("key1")
+	assert.True(t, ok, "Add should clear the tombstone")
+	assert.Equal(t, "stored", val)
+	assert.Equal(t, 2, store.loads)
+}
+
+// Тест: nil из хранилища кешируется как значение и не загружается повторно
+func TestReadThrough_CachesNil(t *testing.T) {
+	store := newFakeStore()
+	store.data["key1"] = nil
+	r := NewLRUReadThrough(2, store, WithNegativeTTL(time.Minute))
+
+	for i := 0; i < 2; i++ {
+		val, ok, err := r.Fetch("key1")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Nil(t, val)
+	}
+	assert.Equal(t, 1, store.loads, "Cached nil should not trigger another load")
+	assert.Equal(t, 1, r.Len())
+}
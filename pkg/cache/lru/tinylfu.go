@@ -0,0 +1,90 @@
+package lru
+
+import (
+	"LRU_cache/pkg/cache/internal/sketch"
+	"sync"
+)
+
+const (
+	sketchDepth   = 4  // число строк Count-Min Sketch
+	sketchSamples = 10 // после capacity*sketchSamples обращений счётчики делятся пополам
+)
+
+// TinyLFU - LRU кеш с фильтром допуска TinyLFU. Частоты всех запрошенных ключей, включая промахи,
+// оцениваются Count-Min Sketch. Когда для нового ключа пришлось бы вытеснить элемент, ключ допускается
+// только если его оценка частоты выше, чем у кандидата на вытеснение. Так однократный проход по большому
+// числу ключей не вымывает часто используемые элементы
+type TinyLFU struct {
+	mu       sync.Mutex
+	lru      *LRU
+	capacity int
+
+	sketch    *sketch.CountMinSketch
+	additions int // обращений с последнего старения sketch
+}
+
+// NewTinyLFULRU создает LRU кеш ёмкостью capacity с фильтром допуска TinyLFU
+func NewTinyLFULRU(capacity int) *TinyLFU {
+	if capacity <= 0 {
+		panic("capacity must be positive")
+	}
+	return &TinyLFU{
+		lru:      NewLRU(capacity),
+		capacity: capacity,
+		sketch:   sketch.New(4*capacity, sketchDepth),
+	}
+}
+
+// Add учитывает обращение к ключу и добавляет значение. Существующий ключ обновляется и возвращается false.
+// Если кеш заполнен и ключ встречался не чаще кандидата на вытеснение, значение отклоняется и возвращается false
+func (t *TinyLFU) Add(key, value interface{}) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.record(key)
+	if _, exists := t.lru.Peek(key); exists || t.lru.Len() < t.capacity {
+		return t.lru.Add(key, value)
+	}
+
+	victim, _, _ := t.lru.PeekOldest()
+	if t.sketch.Estimate(key) <= t.sketch.Estimate(victim) {
+		return false
+	}
+	return t.lru.Add(key, value)
+}
+
+// Get учитывает обращение к ключу (в том числе промах) и читает значение из LRU
+func (t *TinyLFU) Get(key interface{}) (value interface{}, ok bool) {
+	t.mu.Lock()
+	t.record(key)
+	t.mu.Unlock()
+	return t.lru.Get(key)
+}
+
+// Remove удаляет значение; накопленная частота ключа сохраняется
+func (t *TinyLFU) Remove(key interface{}) (ok bool) {
+	return t.lru.Remove(key)
+}
+
+func (t *TinyLFU) Len() int {
+	return t.lru.Len()
+}
+
+// Clear очищает кеш и сбрасывает оценки частот
+func (t *TinyLFU) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lru.Clear()
+	t.sketch.Reset()
+	t.additions = 0
+}
+
+// record учитывает обращение к ключу и после capacity*sketchSamples обращений старит sketch
+func (t *TinyLFU) record(key interface{}) {
+	t.sketch.Increment(key)
+	t.additions++
+	if t.additions >= t.capacity*sketchSamples {
+		t.sketch.Halve()
+		t.additions = 0
+	}
+}
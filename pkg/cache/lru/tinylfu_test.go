@@ -0,0 +1,84 @@
+package lru
+
+import (
+	"LRU_cache/pkg/cache"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ cache.Cache = (*TinyLFU)(nil)
+
+// Тест: частые ключи переживают однократные проходы, которые полностью вымывают обычный LRU
+func TestTinyLFU_ScanResistance(t *testing.T) {
+	const capacity = 10
+	hot := []string{"hot0", "hot1", "hot2", "hot3", "hot4"}
+
+	run := func(c cache.Cache) (hits int) {
+		for _, key := range hot {
+			c.Add(key, key)
+			for i := 0; i < 3; i++ {
+				c.Get(key)
+			}
+		}
+		for round := 0; round < 50; round++ {
+			for i := 0; i < capacity; i++ {
+				key := fmt.Sprintf("scan%d-%d", round, i)
+				c.Add(key, key)
+			}
+			for _, key := range hot {
+				if _, ok := c.Get(key); ok {
+					hits++
+				} else {
+					c.Add(key, key)
+				}
+			}
+		}
+		return hits
+	}
+
+	tiny := NewTinyLFULRU(capacity)
+	assert.Equal(t, 50*len(hot), run(tiny), "TinyLFU should keep every hot key")
+	assert.Equal(t, 0, run(NewLRU(capacity)), "Plain LRU loses hot keys to each scan")
+	assert.Equal(t, capacity, tiny.Len())
+}
+
+// Тест: пока кеш не заполнен, ключи допускаются без сравнения частот
+func TestTinyLFU_AdmitsUntilFull(t *testing.T) {
+	c := NewTinyLFULRU(2)
+	assert.True(t, c.Add("a", 1))
+	assert.True(t, c.Add("b", 2))
+	assert.False(t, c.Add("a", 10), "Existing key is updated")
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	assert.False(t, c.Add("c", 3), "Cold key should not displace the victim")
+	_, ok = c.Get("c")
+	assert.False(t, ok)
+
+	c.Get("c")
+	c.Get("c")
+	assert.True(t, c.Add("c", 3), "Key requested more often than the victim is admitted")
+	_, ok = c.Get("b")
+	assert.False(t, ok, "b was the LRU victim")
+	assert.Equal(t, 2, c.Len())
+}
+
+// Тест: после capacity*sketchSamples обращений оценки частот делятся пополам
+func TestTinyLFU_Aging(t *testing.T) {
+	c := NewTinyLFULRU(1)
+	for i := 0; i < 6; i++ {
+		c.Get("a")
+	}
+	assert.Equal(t, uint64(6), c.sketch.Estimate("a"))
+
+	for i := 0; i < 4; i++ {
+		c.Get("b") // на десятом обращении срабатывает старение
+	}
+	assert.Equal(t, uint64(3), c.sketch.Estimate("a"))
+	assert.Equal(t, uint64(2), c.sketch.Estimate("b"))
+	assert.Equal(t, 0, c.additions)
+}
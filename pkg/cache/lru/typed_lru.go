@@ -0,0 +1,116 @@
+package lru
+
+// typedEntry - узел двусвязного списка типизированного LRU
+type typedEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *typedEntry[K, V]
+}
+
+// TypedLRU - типобезопасный LRU кеш на дженериках.
+// Использует собственный двусвязный список, поэтому не упаковывает значения в interface{}
+type TypedLRU[K comparable, V any] struct {
+	capacity int
+	items    map[K]*typedEntry[K, V]
+	head     *typedEntry[K, V] // самый недавно использованный элемент
+	tail     *typedEntry[K, V] // кандидат на вытеснение
+}
+
+// NewTypedLRU создает типизированный LRU кеш. Как и NewLRUCache, паникует на отрицательной ёмкости
+func NewTypedLRU[K comparable, V any](n int) *TypedLRU[K, V] {
+	if n < 0 {
+		panic("capacity must not be negative")
+	}
+	return &TypedLRU[K, V]{
+		capacity: n,
+		items:    make(map[K]*typedEntry[K, V]),
+	}
+}
+
+// Add добавляет значение с наивысшим приоритетом, возвращает true для нового ключа.
+// Для существующего ключа значение обновляется и возвращается false
+func (L *TypedLRU[K, V]) Add(key K, value V) bool {
+	if entry, exists := L.items[key]; exists {
+		entry.value = value
+		L.moveToFront(entry)
+		return false
+	}
+
+	if L.capacity == 0 {
+		return true
+	}
+
+	if len(L.items) == L.capacity {
+		L.removeEntry(L.tail)
+	}
+
+	entry := &typedEntry[K, V]{key: key, value: value}
+	L.pushFront(entry)
+	L.items[key] = entry
+
+	return true
+}
+
+// Get возвращает значение и флаг наличия, повышая приоритет найденного элемента
+func (L *TypedLRU[K, V]) Get(key K) (value V, ok bool) {
+	entry, exists := L.items[key]
+	if !exists {
+		return value, false
+	}
+	L.moveToFront(entry)
+	return entry.value, true
+}
+
+// Remove удаляет элемент, возвращает false, если ключа нет в кеше
+func (L *TypedLRU[K, V]) Remove(key K) bool {
+	entry, exists := L.items[key]
+	if !exists {
+		return false
+	}
+	L.removeEntry(entry)
+	return true
+}
+
+// Len возвращает количество элементов в кеше
+func (L *TypedLRU[K, V]) Len() int {
+	return len(L.items)
+}
+
+func (L *TypedLRU[K, V]) pushFront(entry *typedEntry[K, V]) {
+	entry.prev = nil
+	entry.next = L.head
+	if L.head != nil {
+		L.head.prev = entry
+	}
+	L.head = entry
+	if L.tail == nil {
+		L.tail = entry
+	}
+}
+
+func (L *TypedLRU[K, V]) unlink(entry *typedEntry[K, V]) {
+	if entry.prev != nil {
+		entry.prev.next = entry.next
+	} else {
+		L.head = entry.next
+	}
+	if entry.next != nil {
+		entry.next.prev = entry.prev
+	} else {
+		L.tail = entry.prev
+	}
+	entry.prev, entry.next = nil, nil
+}
+
+func (L *TypedLRU[K, V]) moveToFront(entry *typedEntry[K, V]) {
+	if L.head == entry {
+		return
+	}
+	L.unlink(entry)
+	L.pushFront(entry)
+}
+
+func (L *TypedLRU[K, V]) removeEntry(entry *typedEntry[K, V]) {
+	L.unlink(entry)
+	delete(L.items, entry.key)
+}
@@ -0,0 +1,82 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест: типизированный кеш со строковыми ключами и целыми значениями
+func TestTypedLRU_StringInt(t *testing.T) {
+	lru := NewTypedLRU[string, int](2)
+
+	assert.True(t, lru.Add("one", 1))
+	assert.True(t, lru.Add("two", 2))
+	assert.False(t, lru.Add("one", 11), "Existing key should return false")
+
+	val, ok := lru.Get("one")
+	assert.True(t, ok)
+	assert.Equal(t, 11, val, "Value should be updated")
+
+	lru.Add("three", 3) // вытесняется two
+	_, ok = lru.Get("two")
+	assert.False(t, ok, "two should be evicted")
+	assert.Equal(t, 2, lru.Len())
+
+	val, ok = lru.Get("missing")
+	assert.False(t, ok)
+	assert.Equal(t, 0, val, "Miss should return the zero value")
+}
+
+// Тест: типизированный кеш с целыми ключами и структурой в значении
+func TestTypedLRU_StructValue(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+	lru := NewTypedLRU[int, user](3)
+	lru.Add(1, user{Name: "alice", Age: 30})
+	lru.Add(2, user{Name: "bob", Age: 25})
+
+	u, ok := lru.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, user{Name: "alice", Age: 30}, u)
+
+	assert.True(t, lru.Remove(1))
+	assert.False(t, lru.Remove(1))
+	_, ok = lru.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, 1, lru.Len())
+}
+
+// Тест: порядок вытеснения совпадает с интерфейсной версией LRU
+func TestTypedLRU_EvictionOrder(t *testing.T) {
+	lru := NewTypedLRU[string, string](3)
+	lru.Add("a", "1")
+	lru.Add("b", "2")
+	lru.Add("c", "3")
+	lru.Get("a") // a -> c -> b
+
+	lru.Add("d", "4") // вытесняется b
+	_, okB := lru.Get("b")
+	assert.False(t, okB, "b should be evicted")
+
+	lru.Add("e", "5") // вытесняется c
+	_, okC := lru.Get("c")
+	assert.False(t, okC, "c should be evicted")
+
+	for _, key := range []string{"a", "d", "e"} {
+		_, ok := lru.Get(key)
+		assert.True(t, ok, "%s should remain", key)
+	}
+}
+
+// Тест: ёмкость 0 ничего не хранит
+func TestTypedLRU_ZeroCapacity(t *testing.T) {
+	lru := NewTypedLRU[string, int](0)
+
+	assert.True(t, lru.Add("key", 1))
+	_, ok := lru.Get("key")
+	assert.False(t, ok)
+	assert.Equal(t, 0, lru.Len())
+}
@@ -0,0 +1,120 @@
+package lruk
+
+import (
+	"LRU_cache/pkg/cache"
+)
+
+// entry - элемент кэша с историей последних обращений
+type entry struct {
+	value   interface{}
+	history []uint64 // моменты последних не более k обращений, самые новые в конце
+}
+
+// LRUK - кэш LRU-K: вытесняется элемент, у которого k-е с конца обращение произошло раньше всех.
+// Элементы, к которым обращались меньше k раз, считаются бесконечно старыми и вытесняются первыми,
+// между собой - в порядке LRU. Поэтому однократный проход по множеству ключей не вытесняет
+// элементы с повторными обращениями. Время логическое: счётчик обращений к кэшу.
+// Выбор жертвы перебирает все элементы, история вытесненных ключей не хранится
+type LRUK struct {
+	capacity int
+	k        int
+	clock    uint64
+	items    map[interface{}]*entry
+}
+
+// NewLRUKCache создает LRU-K кэш на n элементов, учитывающий k последних обращений к каждому ключу.
+// При k = 1 поведение совпадает с LRU
+func NewLRUKCache(n, k int) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	if k <= 0 {
+		panic("k must be positive")
+	}
+	return &LRUK{
+		capacity: n,
+		k:        k,
+		items:    make(map[interface{}]*entry, n),
+	}
+}
+
+// Add добавляет значение и учитывает обращение к ключу. Для существующего ключа обновляет значение и возвращает false
+func (l *LRUK) Add(key, value interface{}) bool {
+	if e, ok := l.items[key]; ok {
+		e.value = value
+		l.touch(e)
+		return false
+	}
+
+	if len(l.items) >= l.capacity {
+		l.evict()
+	}
+	e := &entry{value: value, history: make([]uint64, 0, l.k)}
+	l.touch(e)
+	l.items[key] = e
+	return true
+}
+
+// Get возвращает значение и учитывает обращение к ключу
+func (l *LRUK) Get(key interface{}) (value interface{}, ok bool) {
+	e, exists := l.items[key]
+	if !exists {
+		return nil, false
+	}
+	l.touch(e)
+	return e.value, true
+}
+
+func (l *LRUK) Remove(key interface{}) (ok bool) {
+	if _, exists := l.items[key]; !exists {
+		return false
+	}
+	delete(l.items, key)
+	return true
+}
+
+func (l *LRUK) Len() int {
+	return len(l.items)
+}
+
+// Clear удаляет все элементы вместе с историей обращений
+func (l *LRUK) Clear() {
+	l.items = make(map[interface{}]*entry, l.capacity)
+}
+
+// touch записывает обращение в историю элемента, храня не более k последних
+func (l *LRUK) touch(e *entry) {
+	l.clock++
+	if len(e.history) == l.k {
+		copy(e.history, e.history[1:])
+		e.history = e.history[:l.k-1]
+	}
+	e.history = append(e.history, l.clock)
+}
+
+// evict удаляет элемент с наибольшим обратным k-расстоянием
+func (l *LRUK) evict() {
+	var victim interface{}
+	var victimEntry *entry
+	for key, e := range l.items {
+		if victimEntry == nil || l.older(e, victimEntry) {
+			victim, victimEntry = key, e
+		}
+	}
+	if victimEntry != nil {
+		delete(l.items, victim)
+	}
+}
+
+// older сообщает, должен ли a вытесняться раньше b
+func (l *LRUK) older(a, b *entry) bool {
+	aFull, bFull := len(a.history) == l.k, len(b.history) == l.k
+	switch {
+	case aFull != bFull:
+		return !aFull
+	case aFull:
+		return a.history[0] < b.history[0]
+	default:
+		return a.history[len(a.history)-1] < b.history[len(b.history)-1]
+	}
+}
@@ -0,0 +1,96 @@
+package lruk
+
+import (
+	"LRU_cache/pkg/cache"
+	"LRU_cache/pkg/cache/lru"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLRUK_Basic проверяет базовые операции интерфейса
+func TestLRUK_Basic(t *testing.T) {
+	c := NewLRUKCache(2, 2).(*LRUK)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = c.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.Len())
+}
+
+// TestLRUK_ScanThenReuse сравнивает LRU-2 и обычный LRU: после прохода по новым ключам
+// LRU-2 сохраняет ключи с повторными обращениями, а LRU их теряет
+func TestLRUK_ScanThenReuse(t *testing.T) {
+	run := func(c cache.Cache) (hits int) {
+		for _, key := range []string{"a", "b"} {
+			c.Add(key, key)
+			c.Get(key)
+		}
+		for i := 0; i < 10; i++ {
+			c.Add(fmt.Sprintf("scan%d", i), i)
+		}
+		for _, key := range []string{"a", "b"} {
+			if _, ok := c.Get(key); ok {
+				hits++
+			}
+		}
+		return hits
+	}
+
+	assert.Equal(t, 2, run(NewLRUKCache(3, 2)), "LRU-2 should keep reused keys")
+	assert.Equal(t, 0, run(lru.NewLRUCache(3)), "Plain LRU loses reused keys to the scan")
+}
+
+// TestLRUK_OldestKthReference проверяет, что среди элементов с k обращениями вытесняется элемент
+// с самым ранним k-м с конца обращением, а не с самым старым последним
+func TestLRUK_OldestKthReference(t *testing.T) {
+	c := NewLRUKCache(2, 2).(*LRUK)
+	c.Add("a", 1) // 1
+	c.Add("b", 2) // 2
+	c.Get("b")    // 3: история b = [2, 3]
+	c.Get("a")    // 4: история a = [1, 4]
+
+	c.Add("c", 3) // c одиночный, но вытеснять приходится из полных историй: жертва a
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.items["a"]
+	assert.False(t, ok, "a has the oldest second-to-last reference")
+	_, ok = c.items["b"]
+	assert.True(t, ok)
+
+	c.Add("d", 4) // одиночные c и d: c вытесняется первым как бесконечно старый
+	_, ok = c.items["c"]
+	assert.False(t, ok)
+	_, ok = c.items["b"]
+	assert.True(t, ok)
+}
+
+// TestLRUK_KOneIsLRU проверяет, что при k = 1 порядок вытеснения совпадает с LRU
+func TestLRUK_KOneIsLRU(t *testing.T) {
+	c := NewLRUKCache(2, 1)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Get("a")
+	c.Add("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+}
+
+// TestNewLRUKCache_Invalid проверяет параметры конструктора
+func TestNewLRUKCache_Invalid(t *testing.T) {
+	assert.Panics(t, func() { NewLRUKCache(0, 2) })
+	assert.Panics(t, func() { NewLRUKCache(2, 0) })
+}
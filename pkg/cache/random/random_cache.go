@@ -0,0 +1,91 @@
+package random
+
+import (
+	"LRU_cache/pkg/cache"
+	"math/rand"
+)
+
+// entry - элемент кэша
+type entry struct {
+	key   interface{}
+	value interface{}
+}
+
+// Random - кэш со случайным вытеснением: при переполнении удаляется равновероятно выбранный элемент.
+// Элементы хранятся в срезе, поэтому выбор и удаление жертвы выполняются за O(1)
+type Random struct {
+	capacity int
+	entries  []entry
+	index    map[interface{}]int // ключ -> позиция в entries
+	rng      *rand.Rand
+}
+
+// NewRandomCache создает кэш на n элементов. Генератор инициализируется seed,
+// поэтому при одинаковой последовательности операций вытесняются одни и те же ключи
+func NewRandomCache(n int, seed int64) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	return &Random{
+		capacity: n,
+		entries:  make([]entry, 0, n),
+		index:    make(map[interface{}]int, n),
+		rng:      rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Add добавляет значение, при переполнении вытесняя случайный элемент.
+// Для существующего ключа обновляет значение и возвращает false
+func (r *Random) Add(key, value interface{}) bool {
+	if i, ok := r.index[key]; ok {
+		r.entries[i].value = value
+		return false
+	}
+
+	if len(r.entries) >= r.capacity {
+		r.removeAt(r.rng.Intn(len(r.entries)))
+	}
+	r.index[key] = len(r.entries)
+	r.entries = append(r.entries, entry{key: key, value: value})
+	return true
+}
+
+// Get возвращает значение; обращения на вытеснение не влияют
+func (r *Random) Get(key interface{}) (value interface{}, ok bool) {
+	i, exists := r.index[key]
+	if !exists {
+		return nil, false
+	}
+	return r.entries[i].value, true
+}
+
+func (r *Random) Remove(key interface{}) (ok bool) {
+	i, exists := r.index[key]
+	if !exists {
+		return false
+	}
+	r.removeAt(i)
+	return true
+}
+
+func (r *Random) Len() int {
+	return len(r.entries)
+}
+
+// Clear удаляет все элементы; состояние генератора сохраняется
+func (r *Random) Clear() {
+	r.entries = r.entries[:0]
+	r.index = make(map[interface{}]int, r.capacity)
+}
+
+// removeAt удаляет элемент, переставляя на его место последний элемент среза
+func (r *Random) removeAt(i int) {
+	last := len(r.entries) - 1
+	delete(r.index, r.entries[i].key)
+	if i != last {
+		r.entries[i] = r.entries[last]
+		r.index[r.entries[i].key] = i
+	}
+	r.entries[last] = entry{}
+	r.entries = r.entries[:last]
+}
@@ -0,0 +1,65 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRandom_Basic проверяет базовые операции интерфейса
+func TestRandom_Basic(t *testing.T) {
+	c := NewRandomCache(2, 1).(*Random)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = c.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.Len())
+}
+
+// TestRandom_Capacity проверяет, что размер не превышает ёмкость, а индекс согласован со срезом
+func TestRandom_Capacity(t *testing.T) {
+	c := NewRandomCache(5, 42).(*Random)
+	for i := 0; i < 100; i++ {
+		c.Add(i, i)
+		assert.LessOrEqual(t, c.Len(), 5)
+	}
+	assert.Equal(t, 5, c.Len())
+
+	for key, i := range c.index {
+		assert.Equal(t, key, c.entries[i].key)
+		val, ok := c.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, key, val)
+	}
+}
+
+// TestRandom_Reproducible проверяет, что при одном seed вытесняются одни и те же ключи
+func TestRandom_Reproducible(t *testing.T) {
+	survivors := func(seed int64) map[interface{}]bool {
+		c := NewRandomCache(4, seed).(*Random)
+		for i := 0; i < 20; i++ {
+			c.Add(i, i)
+			if i%3 == 0 {
+				c.Remove(i - 1)
+			}
+		}
+		keys := map[interface{}]bool{}
+		for key := range c.index {
+			keys[key] = true
+		}
+		return keys
+	}
+
+	assert.Equal(t, survivors(7), survivors(7))
+	assert.Len(t, survivors(7), 4)
+}
@@ -0,0 +1,183 @@
+package s3fifo
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/list"
+)
+
+const (
+	// SmallRatio - доля ёмкости под малую очередь S
+	SmallRatio = 0.1
+	// maxFreq - предел счётчика обращений (два бита, как в оригинальном алгоритме)
+	maxFreq = 3
+)
+
+// entry - элемент одной из очередей S3-FIFO. У призрачных записей G значение не хранится
+type entry struct {
+	key   interface{}
+	value interface{}
+	freq  int        // счётчик обращений, не больше maxFreq
+	queue *list.List // очередь, в которой сейчас находится элемент
+}
+
+// S3FIFO - кэш S3-FIFO из трёх FIFO-очередей: новые ключи попадают в малую очередь S,
+// получившие обращение за время пребывания в S переходят в основную очередь M, а остальные
+// вытесняются и запоминаются в призрачной очереди G. Повторно добавленный из G ключ сразу попадает в M.
+// M вытесняет как CLOCK: элемент с обращениями возвращается в начало с уменьшенным счётчиком
+type S3FIFO struct {
+	capacity  int
+	smallSize int // целевой размер S
+	ghostSize int // максимальный размер G
+
+	small *list.List // S, в начале самые новые элементы
+	main  *list.List // M, в начале самые новые элементы
+	ghost *list.List // G, призрачные ключи, вытесненные из S
+
+	items map[interface{}]*list.Element
+}
+
+// NewS3FIFOCache создает S3-FIFO кэш на n элементов. S занимает SmallRatio ёмкости (минимум один элемент),
+// G помнит столько ключей, сколько помещается в M
+func NewS3FIFOCache(n int) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	smallSize := maxInt(1, int(float64(n)*SmallRatio))
+	return &S3FIFO{
+		capacity:  n,
+		smallSize: smallSize,
+		ghostSize: maxInt(1, n-smallSize),
+		small:     list.New(),
+		main:      list.New(),
+		ghost:     list.New(),
+		items:     make(map[interface{}]*list.Element),
+	}
+}
+
+// Add добавляет значение. Существующий резидентный ключ обновляется, получает обращение и возвращается false;
+// ключ, найденный в G, попадает в M
+func (s *S3FIFO) Add(key, value interface{}) bool {
+	if element, ok := s.items[key]; ok {
+		e := element.Value.(*entry)
+		if e.queue != s.ghost {
+			e.value = value
+			e.touch()
+			return false
+		}
+		s.ghost.Remove(element)
+		delete(s.items, key)
+		s.reclaim()
+		s.push(s.main, key, value)
+		return true
+	}
+
+	s.reclaim()
+	s.push(s.small, key, value)
+	return true
+}
+
+// Get возвращает резидентное значение и увеличивает счётчик обращений, не перемещая элемент
+func (s *S3FIFO) Get(key interface{}) (value interface{}, ok bool) {
+	element, exists := s.items[key]
+	if !exists {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if e.queue == s.ghost {
+		return nil, false
+	}
+	e.touch()
+	return e.value, true
+}
+
+// Remove удаляет резидентный элемент; призрачная запись ключа тоже забывается
+func (s *S3FIFO) Remove(key interface{}) (ok bool) {
+	element, exists := s.items[key]
+	if !exists {
+		return false
+	}
+	e := element.Value.(*entry)
+	e.queue.Remove(element)
+	delete(s.items, key)
+	return e.queue != s.ghost
+}
+
+// Len возвращает количество резидентных элементов
+func (s *S3FIFO) Len() int {
+	return s.small.Len() + s.main.Len()
+}
+
+// Clear очищает все очереди, включая призрачную G
+func (s *S3FIFO) Clear() {
+	s.small.Init()
+	s.main.Init()
+	s.ghost.Init()
+	s.items = make(map[interface{}]*list.Element)
+}
+
+// reclaim освобождает место под новый элемент, если кэш заполнен.
+// Пока S не меньше целевого размера, вытеснение идёт из S, иначе из M
+func (s *S3FIFO) reclaim() {
+	for s.Len() >= s.capacity {
+		if s.small.Len() >= s.smallSize || s.main.Len() == 0 {
+			s.evictSmall()
+		} else {
+			s.evictMain()
+		}
+	}
+}
+
+// evictSmall убирает старейший элемент S: с обращениями он переходит в M, без обращений - в G
+func (s *S3FIFO) evictSmall() {
+	e := s.small.Remove(s.small.Back()).(*entry)
+	if e.freq > 0 {
+		e.freq = 0
+		e.queue = s.main
+		s.items[e.key] = s.main.PushFront(e)
+		return
+	}
+
+	e.value = nil
+	e.queue = s.ghost
+	s.items[e.key] = s.ghost.PushFront(e)
+	if s.ghost.Len() > s.ghostSize {
+		old := s.ghost.Remove(s.ghost.Back()).(*entry)
+		delete(s.items, old.key)
+	}
+}
+
+// evictMain вытесняет из M первый с конца элемент без обращений; элементы с обращениями
+// возвращаются в начало M с уменьшенным счётчиком
+func (s *S3FIFO) evictMain() {
+	for {
+		element := s.main.Back()
+		e := element.Value.(*entry)
+		if e.freq > 0 {
+			e.freq--
+			s.main.MoveToFront(element)
+			continue
+		}
+		s.main.Remove(element)
+		delete(s.items, e.key)
+		return
+	}
+}
+
+// push добавляет резидентный элемент в начало очереди
+func (s *S3FIFO) push(queue *list.List, key, value interface{}) {
+	s.items[key] = queue.PushFront(&entry{key: key, value: value, queue: queue})
+}
+
+// touch отмечает обращение к элементу
+func (e *entry) touch() {
+	if e.freq < maxFreq {
+		e.freq++
+	}
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,90 @@
+package s3fifo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestS3FIFO_Basic проверяет базовые операции интерфейса
+func TestS3FIFO_Basic(t *testing.T) {
+	s := NewS3FIFOCache(4).(*S3FIFO)
+
+	assert.True(t, s.Add("a", 1))
+	assert.False(t, s.Add("a", 10))
+
+	val, ok := s.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = s.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, s.Remove("a"))
+	assert.False(t, s.Remove("a"))
+	assert.Equal(t, 0, s.Len())
+}
+
+// TestS3FIFO_OneHitWonderEvicted проверяет, что ключ без повторных обращений быстро уходит в G,
+// а ключ с обращением переходит в M и переживает поток новых ключей
+func TestS3FIFO_OneHitWonderEvicted(t *testing.T) {
+	s := NewS3FIFOCache(10).(*S3FIFO)
+	s.Add("hot", 0)
+	s.Get("hot")
+	s.Add("once", 0)
+	for i := 0; i < 8; i++ {
+		s.Add(fmt.Sprintf("fill%d", i), i)
+	}
+
+	s.Add("new", 0) // hot переходит в M, once вытесняется в G
+	assert.Equal(t, s.main, s.items["hot"].Value.(*entry).queue, "hot should be promoted to M")
+	assert.Equal(t, s.ghost, s.items["once"].Value.(*entry).queue, "once should be remembered in G")
+	_, ok := s.Get("once")
+	assert.False(t, ok, "once should not be resident")
+
+	for i := 0; i < 20; i++ {
+		s.Add(fmt.Sprintf("stream%d", i), i)
+	}
+	val, ok := s.Get("hot")
+	assert.True(t, ok, "hot should be retained in M")
+	assert.Equal(t, 0, val)
+	assert.Equal(t, 10, s.Len())
+}
+
+// TestS3FIFO_GhostHitGoesToMain проверяет, что ключ из G при повторном добавлении попадает сразу в M
+func TestS3FIFO_GhostHitGoesToMain(t *testing.T) {
+	s := NewS3FIFOCache(4).(*S3FIFO)
+	for i := 0; i < 5; i++ {
+		s.Add(i, i)
+	}
+	assert.Equal(t, s.ghost, s.items[0].Value.(*entry).queue)
+
+	assert.True(t, s.Add(0, 100), "Re-adding a ghost key counts as a new entry")
+	assert.Equal(t, s.main, s.items[0].Value.(*entry).queue)
+	val, ok := s.Get(0)
+	assert.True(t, ok)
+	assert.Equal(t, 100, val)
+	assert.Equal(t, 4, s.Len())
+}
+
+// TestS3FIFO_MainSecondChance проверяет, что элемент M с обращениями возвращается в начало M
+func TestS3FIFO_MainSecondChance(t *testing.T) {
+	s := NewS3FIFOCache(2).(*S3FIFO)
+	s.Add("a", 1)
+	s.Get("a")
+	s.Add("b", 2)
+	s.Get("b")
+	s.Add("c", 3) // a и b переходят в M, затем вытесняется a
+
+	assert.Equal(t, 2, s.Len())
+	_, ok := s.Get("a")
+	assert.False(t, ok)
+	s.Get("b")    // b снова получает обращение
+	s.Add("d", 4) // S заполнена: c без обращений уходит в G
+	_, ok = s.Get("b")
+	assert.True(t, ok, "b with references should survive")
+	_, ok = s.Get("c")
+	assert.False(t, ok)
+}
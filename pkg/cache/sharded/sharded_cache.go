@@ -0,0 +1,153 @@
+package sharded
+
+import (
+	"LRU_cache/pkg/cache"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+)
+
+// shard - независимый подкэш со своей блокировкой
+type shard struct {
+	mu    sync.Mutex
+	cache cache.Cache
+
+	hits   uint64
+	misses uint64
+}
+
+// Stats - статистика одного шарда или сумма по всем шардам
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // вытеснения считаются, только если подкэш реализует Evictions() uint64
+	Size      int
+}
+
+// evictionCounter - подкэш, который считает вытеснения (например, *lru.LRU и *lfu.LFUCache)
+type evictionCounter interface {
+	Evictions() uint64
+}
+
+// ShardedCache распределяет ключи по нескольким подкэшам по хешу ключа,
+// чтобы конкурирующие горутины реже ждали одну и ту же блокировку
+type ShardedCache struct {
+	shards []*shard
+}
+
+// NewShardedCache создает кэш из shardCount шардов, каждый из которых строит factory.
+// Ёмкость делится между шардами поровну, остаток достаётся первым шардам
+func NewShardedCache(capacity, shardCount int, factory func(capacity int) cache.Cache) cache.Cache {
+	if shardCount <= 0 {
+		panic("shard count must be positive")
+	}
+	if capacity < shardCount {
+		panic("capacity must be at least the shard count")
+	}
+
+	shards := make([]*shard, shardCount)
+	for i := range shards {
+		shardCapacity := capacity / shardCount
+		if i < capacity%shardCount {
+			shardCapacity++
+		}
+		shards[i] = &shard{cache: factory(shardCapacity)}
+	}
+	return &ShardedCache{shards: shards}
+}
+
+func (s *ShardedCache) Add(key, value interface{}) bool {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.cache.Add(key, value)
+}
+
+func (s *ShardedCache) Get(key interface{}) (value interface{}, ok bool) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	value, ok = sh.cache.Get(key)
+	sh.mu.Unlock()
+
+	if ok {
+		atomic.AddUint64(&sh.hits, 1)
+	} else {
+		atomic.AddUint64(&sh.misses, 1)
+	}
+	return value, ok
+}
+
+func (s *ShardedCache) Remove(key interface{}) (ok bool) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.cache.Remove(key)
+}
+
+// Len возвращает суммарное количество элементов во всех шардах
+func (s *ShardedCache) Len() int {
+	total := 0
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		total += sh.cache.Len()
+		sh.mu.Unlock()
+	}
+	return total
+}
+
+// Clear по очереди очищает все шарды. Статистика обращений сохраняется
+func (s *ShardedCache) Clear() {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		sh.cache.Clear()
+		sh.mu.Unlock()
+	}
+}
+
+// Stats возвращает попадания, промахи, вытеснения и размер, сложенные по всем шардам
+func (s *ShardedCache) Stats() Stats {
+	var total Stats
+	for _, sh := range s.shards {
+		stats := sh.stats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Evictions += stats.Evictions
+		total.Size += stats.Size
+	}
+	return total
+}
+
+// ShardStats возвращает статистику каждого шарда по порядку, чтобы были видны перекосы нагрузки
+func (s *ShardedCache) ShardStats() []Stats {
+	stats := make([]Stats, len(s.shards))
+	for i, sh := range s.shards {
+		stats[i] = sh.stats()
+	}
+	return stats
+}
+
+// stats собирает статистику шарда
+func (sh *shard) stats() Stats {
+	sh.mu.Lock()
+	stats := Stats{Size: sh.cache.Len()}
+	if counter, ok := sh.cache.(evictionCounter); ok {
+		stats.Evictions = counter.Evictions()
+	}
+	sh.mu.Unlock()
+
+	stats.Hits = atomic.LoadUint64(&sh.hits)
+	stats.Misses = atomic.LoadUint64(&sh.misses)
+	return stats
+}
+
+// shardFor выбирает шард по FNV-хешу типа и строкового представления ключа
+func (s *ShardedCache) shardFor(key interface{}) *shard {
+	return s.shards[shardIndex(key, len(s.shards))]
+}
+
+func shardIndex(key interface{}, shardCount int) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%T:%v", key, key)
+	return int(h.Sum32() % uint32(shardCount))
+}
@@ -0,0 +1,124 @@
+package sharded
+
+import (
+	"LRU_cache/pkg/cache"
+	"LRU_cache/pkg/cache/lfu"
+	"LRU_cache/pkg/cache/lru"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLRUShard(capacity int) cache.Cache {
+	return lru.NewLRUCache(capacity)
+}
+
+// TestSharded_Basic проверяет базовые операции интерфейса
+func TestSharded_Basic(t *testing.T) {
+	c := NewShardedCache(8, 4, newLRUShard)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 2))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+
+	assert.True(t, c.Remove("a"))
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+}
+
+// TestSharded_DeterministicShards проверяет, что ключ всегда попадает в один и тот же шард
+func TestSharded_DeterministicShards(t *testing.T) {
+	c := NewShardedCache(200, 4, newLRUShard).(*ShardedCache)
+
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("key%d", i)
+		idx := shardIndex(key, 4)
+		assert.Equal(t, idx, shardIndex(key, 4), "Shard index should be stable")
+
+		c.Add(key, i)
+		for j, sh := range c.shards {
+			_, found := sh.cache.Get(key)
+			assert.Equal(t, j == idx, found, "Key should live only in its own shard")
+		}
+	}
+}
+
+// TestSharded_CapacityDivided проверяет распределение ёмкости между шардами
+func TestSharded_CapacityDivided(t *testing.T) {
+	var capacities []int
+	NewShardedCache(10, 4, func(capacity int) cache.Cache {
+		capacities = append(capacities, capacity)
+		return lfu.NewLFUCache(capacity)
+	})
+	assert.Equal(t, []int{3, 3, 2, 2}, capacities)
+
+	c := NewShardedCache(100, 4, newLRUShard).(*ShardedCache)
+	for i := 0; i < 1000; i++ {
+		c.Add(i, i)
+	}
+	assert.LessOrEqual(t, c.Len(), 100, "Total size should not exceed capacity")
+	assert.Greater(t, c.Len(), 80, "Shards should be filled roughly evenly")
+}
+
+// TestSharded_Stats проверяет суммирование статистики и конкурентный доступ
+func TestSharded_Stats(t *testing.T) {
+	c := NewShardedCache(64, 4, newLRUShard).(*ShardedCache)
+	for i := 0; i < 32; i++ {
+		c.Add(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 64; i++ {
+				c.Get(i) // 32 попадания и 32 промаха
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, Stats{Hits: 8 * 32, Misses: 8 * 32, Size: 32}, c.Stats())
+}
+
+// TestSharded_ShardStats проверяет, что неравномерное распределение ключей видно по шардам,
+// а сумма совпадает с общей статистикой
+func TestSharded_ShardStats(t *testing.T) {
+	c := NewShardedCache(40, 4, newLRUShard).(*ShardedCache)
+
+	hot, cold := 0, 0
+	for i := 0; hot < 15 || cold < 2; i++ {
+		key := fmt.Sprintf("key%d", i)
+		switch shardIndex(key, 4) {
+		case 0:
+			if hot < 15 {
+				c.Add(key, i)
+				hot++
+			}
+		case 1:
+			if cold < 2 {
+				c.Add(key, i)
+				cold++
+			}
+		}
+	}
+	c.Get("missing")
+
+	stats := c.ShardStats()
+	assert.Len(t, stats, 4)
+	assert.Equal(t, []int{10, 2, 0, 0}, []int{stats[0].Size, stats[1].Size, stats[2].Size, stats[3].Size},
+		"Hot shard should be full while others stay small")
+	assert.Equal(t, uint64(5), stats[0].Evictions, "Overflowing hot shard should evict")
+
+	total := c.Stats()
+	assert.Equal(t, 12, total.Size)
+	assert.Equal(t, c.Len(), total.Size)
+	assert.Equal(t, uint64(5), total.Evictions)
+	assert.Equal(t, uint64(1), total.Misses)
+}
@@ -0,0 +1,115 @@
+package sieve
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/list"
+)
+
+// entry - элемент очереди SIEVE
+type entry struct {
+	key     interface{}
+	value   interface{}
+	visited bool // бит обращения, выставляется в Get
+}
+
+// Sieve - кэш SIEVE: элементы лежат в FIFO-очереди, Get выставляет бит обращения, не перемещая элемент,
+// а стрелка при вытеснении идёт от старых элементов к новым, сбрасывая биты до первого элемента без обращений.
+// В отличие от CLOCK, пережившие проход элементы остаются на месте, а новые всегда встают в начало очереди
+type Sieve struct {
+	capacity int
+	queue    *list.List // в начале самые новые элементы
+	items    map[interface{}]*list.Element
+	hand     *list.Element // позиция стрелки; nil - начать с конца очереди
+}
+
+// NewSieveCache создает SIEVE кэш на n элементов
+func NewSieveCache(n int) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	return &Sieve{
+		capacity: n,
+		queue:    list.New(),
+		items:    make(map[interface{}]*list.Element, n),
+	}
+}
+
+// Add добавляет значение в начало очереди. Для существующего ключа обновляет значение,
+// выставляет бит обращения и возвращает false
+func (s *Sieve) Add(key, value interface{}) bool {
+	if element, ok := s.items[key]; ok {
+		e := element.Value.(*entry)
+		e.value = value
+		e.visited = true
+		return false
+	}
+
+	if s.queue.Len() >= s.capacity {
+		s.evict()
+	}
+	s.items[key] = s.queue.PushFront(&entry{key: key, value: value})
+	return true
+}
+
+// Get возвращает значение и выставляет бит обращения, не перемещая элемент
+func (s *Sieve) Get(key interface{}) (value interface{}, ok bool) {
+	element, exists := s.items[key]
+	if !exists {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	e.visited = true
+	return e.value, true
+}
+
+func (s *Sieve) Remove(key interface{}) (ok bool) {
+	element, exists := s.items[key]
+	if !exists {
+		return false
+	}
+	s.remove(element)
+	return true
+}
+
+func (s *Sieve) Len() int {
+	return s.queue.Len()
+}
+
+// Clear удаляет все элементы и возвращает стрелку в конец очереди
+func (s *Sieve) Clear() {
+	s.queue.Init()
+	s.items = make(map[interface{}]*list.Element, s.capacity)
+	s.hand = nil
+}
+
+// evict двигает стрелку к началу очереди, сбрасывая биты обращения, и вытесняет первый элемент без обращений.
+// Дойдя до начала, стрелка возвращается в конец очереди
+func (s *Sieve) evict() {
+	element := s.hand
+	if element == nil {
+		element = s.queue.Back()
+	}
+	for element != nil {
+		e := element.Value.(*entry)
+		if !e.visited {
+			break
+		}
+		e.visited = false
+		if element = element.Prev(); element == nil {
+			element = s.queue.Back()
+		}
+	}
+	if element != nil {
+		s.hand = element
+		s.remove(element)
+	}
+}
+
+// remove удаляет элемент, сдвигая стрелку на следующий по направлению обхода, если она указывала на него
+func (s *Sieve) remove(element *list.Element) {
+	if s.hand == element {
+		s.hand = element.Prev()
+	}
+	e := s.queue.Remove(element).(*entry)
+	delete(s.items, e.key)
+}
@@ -0,0 +1,84 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSieve_Basic проверяет базовые операции интерфейса
+func TestSieve_Basic(t *testing.T) {
+	c := NewSieveCache(2).(*Sieve)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = c.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.Len())
+}
+
+// TestSieve_VisitedSurvives проверяет, что элемент с обращением после вставки переживает первый проход стрелки
+func TestSieve_VisitedSurvives(t *testing.T) {
+	c := NewSieveCache(3).(*Sieve)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	c.Get("a")
+
+	c.Add("d", 4) // стрелка сбрасывает бит a и вытесняет b
+	_, ok := c.Get("b")
+	assert.False(t, ok, "b should be evicted")
+	assert.Equal(t, 3, c.Len())
+
+	c.Add("e", 5) // стрелка продолжает с c, a остаётся позади неё
+	c.Add("f", 6) // затем вытесняется d
+	assert.Equal(t, []interface{}{"f", "e", "a"}, keys(c))
+}
+
+// TestSieve_AllVisited проверяет полный оборот стрелки, когда у всех элементов выставлен бит
+func TestSieve_AllVisited(t *testing.T) {
+	c := NewSieveCache(2).(*Sieve)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Get("a")
+	c.Get("b")
+
+	c.Add("c", 3) // после сброса всех битов вытесняется a
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+// TestSieve_RemoveUnderHand проверяет, что удаление элемента под стрелкой сдвигает её дальше
+func TestSieve_RemoveUnderHand(t *testing.T) {
+	c := NewSieveCache(3).(*Sieve)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	c.Add("d", 4) // вытесняется a, стрелка на b
+
+	c.Get("b")
+	assert.True(t, c.Remove("b")) // стрелка переходит на c
+	c.Add("e", 5)
+	c.Add("f", 6) // вытесняется c
+
+	assert.Equal(t, []interface{}{"f", "e", "d"}, keys(c))
+}
+
+// keys возвращает ключи от начала очереди к концу
+func keys(c *Sieve) []interface{} {
+	var result []interface{}
+	for element := c.queue.Front(); element != nil; element = element.Next() {
+		result = append(result, element.Value.(*entry).key)
+	}
+	return result
+}
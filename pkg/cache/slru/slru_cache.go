@@ -0,0 +1,129 @@
+package slru
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/list"
+)
+
+// DefaultProtectedRatio - доля ёмкости под защищённый сегмент по умолчанию
+const DefaultProtectedRatio = 0.8
+
+// entry - элемент одного из сегментов
+type entry struct {
+	key     interface{}
+	value   interface{}
+	segment *list.List // сегмент, в котором сейчас находится элемент
+}
+
+// SLRU - сегментированный LRU. Новые ключи попадают в испытательный сегмент, попадание переносит
+// ключ в защищённый, а вытесненный из переполненного защищённого сегмента ключ возвращается
+// в начало испытательного. Из кэша вытесняется только конец испытательного сегмента,
+// поэтому однократный проход по множеству ключей не вымывает повторно использованные
+type SLRU struct {
+	capacity     int
+	protectedCap int // максимальный размер защищённого сегмента
+
+	probation *list.List // LRU впервые встреченных элементов, в начале самые недавние
+	protected *list.List // LRU элементов с повторными обращениями
+
+	items map[interface{}]*list.Element
+}
+
+// NewSLRUCache создает SLRU кэш на n элементов с долей защищённого сегмента по умолчанию
+func NewSLRUCache(n int) cache.Cache {
+	return NewSLRUCacheWithRatio(n, DefaultProtectedRatio)
+}
+
+// NewSLRUCacheWithRatio создает SLRU кэш, в котором защищённый сегмент занимает protectedRatio ёмкости.
+// Испытательному сегменту всегда остаётся хотя бы один элемент
+func NewSLRUCacheWithRatio(n int, protectedRatio float64) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	if protectedRatio < 0 || protectedRatio > 1 {
+		panic("invalid protected ratio")
+	}
+	protectedCap := int(float64(n) * protectedRatio)
+	if protectedCap > n-1 {
+		protectedCap = n - 1
+	}
+	return &SLRU{
+		capacity:     n,
+		protectedCap: protectedCap,
+		probation:    list.New(),
+		protected:    list.New(),
+		items:        make(map[interface{}]*list.Element),
+	}
+}
+
+// Add добавляет значение в испытательный сегмент, при переполнении вытесняя конец испытательного сегмента.
+// Существующий ключ обновляется, засчитывается как попадание и возвращается false
+func (s *SLRU) Add(key, value interface{}) bool {
+	if element, ok := s.items[key]; ok {
+		element.Value.(*entry).value = value
+		s.hit(element)
+		return false
+	}
+
+	if len(s.items) >= s.capacity {
+		if element := s.probation.Back(); element != nil {
+			s.probation.Remove(element)
+			delete(s.items, element.Value.(*entry).key)
+		}
+	}
+	s.items[key] = s.probation.PushFront(&entry{key: key, value: value, segment: s.probation})
+	return true
+}
+
+// Get возвращает значение; попадание переносит ключ в начало защищённого сегмента
+func (s *SLRU) Get(key interface{}) (value interface{}, ok bool) {
+	element, exists := s.items[key]
+	if !exists {
+		return nil, false
+	}
+	s.hit(element)
+	return element.Value.(*entry).value, true
+}
+
+func (s *SLRU) Remove(key interface{}) (ok bool) {
+	element, exists := s.items[key]
+	if !exists {
+		return false
+	}
+	element.Value.(*entry).segment.Remove(element)
+	delete(s.items, key)
+	return true
+}
+
+func (s *SLRU) Len() int {
+	return len(s.items)
+}
+
+func (s *SLRU) Clear() {
+	s.probation.Init()
+	s.protected.Init()
+	s.items = make(map[interface{}]*list.Element)
+}
+
+// hit повышает приоритет элемента: из испытательного сегмента он переходит в защищённый,
+// вытесняя при переполнении конец защищённого сегмента обратно в испытательный
+func (s *SLRU) hit(element *list.Element) {
+	e := element.Value.(*entry)
+	if e.segment == s.protected || s.protectedCap == 0 {
+		e.segment.MoveToFront(element)
+		return
+	}
+
+	s.moveTo(element, s.protected)
+	if s.protected.Len() > s.protectedCap {
+		s.moveTo(s.protected.Back(), s.probation)
+	}
+}
+
+// moveTo переносит элемент в начало сегмента target
+func (s *SLRU) moveTo(element *list.Element, target *list.List) {
+	e := element.Value.(*entry)
+	e.segment.Remove(element)
+	e.segment = target
+	s.items[e.key] = target.PushFront(e)
+}
@@ -0,0 +1,64 @@
+package slru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSLRU_Basic проверяет базовые операции интерфейса
+func TestSLRU_Basic(t *testing.T) {
+	c := NewSLRUCache(2).(*SLRU)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 10))
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, 0, c.Len())
+}
+
+// TestSLRU_ProtectedSurvivesScan проверяет, что ключ с повторным обращением попадает в защищённый
+// сегмент и переживает проход, вытесняющий ключи испытательного сегмента
+func TestSLRU_ProtectedSurvivesScan(t *testing.T) {
+	c := NewSLRUCacheWithRatio(4, 0.5).(*SLRU)
+
+	c.Add("hot", 1)
+	c.Get("hot")
+	assert.Equal(t, c.protected, c.items["hot"].Value.(*entry).segment, "Second access should promote to protected")
+
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+	}
+	_, ok := c.Get("hot")
+	assert.True(t, ok, "Protected key should survive the scan")
+	_, ok = c.Get(0)
+	assert.False(t, ok, "Probationary scan keys should be evicted")
+	assert.Equal(t, 4, c.Len())
+}
+
+// TestSLRU_ProtectedOverflowDemotes проверяет возврат лишних ключей из защищённого сегмента в испытательный
+func TestSLRU_ProtectedOverflowDemotes(t *testing.T) {
+	c := NewSLRUCacheWithRatio(4, 0.5).(*SLRU)
+	for _, key := range []string{"a", "b", "c"} {
+		c.Add(key, key)
+		c.Get(key)
+	}
+
+	assert.Equal(t, 2, c.protected.Len())
+	assert.Equal(t, c.probation, c.items["a"].Value.(*entry).segment, "Oldest protected key should be demoted")
+	assert.Equal(t, "a", c.probation.Front().Value.(*entry).key)
+
+	c.Add("d", 4)
+	c.Add("e", 5) // вытесняет a из конца испытательного сегмента
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 4, c.Len())
+}
@@ -0,0 +1,10 @@
+package cache
+
+// Store - внешнее хранилище, за которым стоит кеш (база данных, удалённый сервис и т.п.)
+type Store interface {
+	// Load Загружает значение по ключу; found=false означает, что ключа нет в хранилище
+	Load(key interface{}) (value interface{}, found bool, err error)
+
+	// Save Сохраняет значение по ключу в хранилище
+	Save(key, value interface{}) error
+}
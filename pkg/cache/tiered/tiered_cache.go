@@ -0,0 +1,57 @@
+package tiered
+
+import "LRU_cache/pkg/cache"
+
+// TieredCache объединяет небольшой быстрый кэш L1 и больший кэш L2.
+// Запись идёт в оба уровня, промах в L1 проверяется в L2, а найденное там значение поднимается в L1.
+// Операции над уровнями не атомарны: кэш безопасен для конкурентного использования
+// настолько, насколько безопасны сами L1 и L2
+type TieredCache struct {
+	l1 cache.Cache
+	l2 cache.Cache
+}
+
+// NewTieredCache создает двухуровневый кэш из l1 и l2
+func NewTieredCache(l1, l2 cache.Cache) cache.Cache {
+	if l1 == nil || l2 == nil {
+		panic("both tiers must be non-nil")
+	}
+	return &TieredCache{l1: l1, l2: l2}
+}
+
+// Add записывает значение в оба уровня. Результат определяется L2 как основным хранилищем
+func (t *TieredCache) Add(key, value interface{}) bool {
+	t.l1.Add(key, value)
+	return t.l2.Add(key, value)
+}
+
+// Get ищет ключ в L1, затем в L2. Значение из L2 поднимается в L1; если L1 при этом
+// вытесняет другой элемент, тот остаётся доступен в L2
+func (t *TieredCache) Get(key interface{}) (value interface{}, ok bool) {
+	if value, ok = t.l1.Get(key); ok {
+		return value, true
+	}
+	if value, ok = t.l2.Get(key); !ok {
+		return nil, false
+	}
+	t.l1.Add(key, value)
+	return value, true
+}
+
+// Remove удаляет ключ из обоих уровней и возвращает true, если он был хотя бы в одном
+func (t *TieredCache) Remove(key interface{}) (ok bool) {
+	removed1 := t.l1.Remove(key)
+	removed2 := t.l2.Remove(key)
+	return removed1 || removed2
+}
+
+// Len возвращает количество элементов в L2
+func (t *TieredCache) Len() int {
+	return t.l2.Len()
+}
+
+// Clear очищает оба уровня
+func (t *TieredCache) Clear() {
+	t.l1.Clear()
+	t.l2.Clear()
+}
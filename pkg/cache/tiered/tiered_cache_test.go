@@ -0,0 +1,71 @@
+package tiered
+
+import (
+	"LRU_cache/pkg/cache/lfu"
+	"LRU_cache/pkg/cache/lru"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTiered_AddWritesBothTiers проверяет, что Add записывает значение в оба уровня
+func TestTiered_AddWritesBothTiers(t *testing.T) {
+	l1, l2 := lfu.NewLFUCache(2), lru.NewLRUCache(10)
+	c := NewTieredCache(l1, l2)
+
+	assert.True(t, c.Add("a", 1))
+	assert.False(t, c.Add("a", 2))
+
+	val, ok := l1.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+	val, ok = l2.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+}
+
+// TestTiered_PromotionOnHit проверяет подъём значения из L2 в L1 при попадании
+func TestTiered_PromotionOnHit(t *testing.T) {
+	l1, l2 := lru.NewLRU(1), lru.NewLRU(10)
+	c := NewTieredCache(l1, l2)
+
+	c.Add("a", 1)
+	c.Add("b", 2) // вытесняет a из L1
+	_, ok := l1.Peek("a")
+	assert.False(t, ok)
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	_, ok = l1.Peek("a")
+	assert.True(t, ok, "L2 hit should promote the value into L1")
+
+	// Подъём a вытеснил b из L1, но b по-прежнему доступен через L2
+	_, ok = l1.Peek("b")
+	assert.False(t, ok)
+	val, ok = c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+}
+
+// TestTiered_RemoveAndClear проверяет удаление из обоих уровней
+func TestTiered_RemoveAndClear(t *testing.T) {
+	l1, l2 := lru.NewLRUCache(1), lru.NewLRUCache(10)
+	c := NewTieredCache(l1, l2)
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	assert.True(t, c.Remove("a"), "Key only in L2 should be removed")
+	assert.True(t, c.Remove("b"))
+	assert.False(t, c.Remove("b"))
+	assert.Equal(t, 0, l1.Len())
+	assert.Equal(t, 0, l2.Len())
+
+	c.Add("c", 3)
+	c.Clear()
+	assert.Equal(t, 0, l1.Len())
+	assert.Equal(t, 0, c.Len())
+}
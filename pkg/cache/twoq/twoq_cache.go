@@ -0,0 +1,163 @@
+package twoq
+
+import (
+	"LRU_cache/pkg/cache"
+	"container/list"
+)
+
+const (
+	// DefaultInRatio - доля ёмкости под очередь A1in по умолчанию
+	DefaultInRatio = 0.25
+	// DefaultOutRatio - доля ёмкости под призрачную очередь A1out по умолчанию
+	DefaultOutRatio = 0.5
+)
+
+// entry - элемент одной из очередей 2Q. У призрачных записей A1out значение не хранится
+type entry struct {
+	key   interface{}
+	value interface{}
+	queue *list.List // очередь, в которой сейчас находится элемент
+}
+
+// TwoQ - кэш 2Q: новые ключи попадают в FIFO-очередь A1in, вытесненные из неё ключи запоминаются
+// в призрачной очереди A1out, а повторно добавленный из A1out ключ переходит в LRU-очередь Am
+type TwoQ struct {
+	capacity int
+	kIn      int // целевой размер A1in
+	kOut     int // максимальный размер A1out
+
+	a1in  *list.List // FIFO впервые встреченных элементов, в начале самые новые
+	a1out *list.List // призрачные ключи, вытесненные из A1in
+	am    *list.List // LRU часто используемых элементов
+
+	items map[interface{}]*list.Element
+}
+
+// NewTwoQCache создает 2Q кэш с долями очередей по умолчанию
+func NewTwoQCache(n int) cache.Cache {
+	return NewTwoQCacheWithRatios(n, DefaultInRatio, DefaultOutRatio)
+}
+
+// NewTwoQCacheWithRatios создает 2Q кэш, в котором A1in занимает inRatio ёмкости,
+// а A1out помнит до outRatio ёмкости ключей (каждая очередь - минимум один элемент)
+func NewTwoQCacheWithRatios(n int, inRatio, outRatio float64) cache.Cache {
+	if n <= 0 {
+		panic("capacity must be positive")
+	}
+	if inRatio < 0 || inRatio > 1 || outRatio < 0 {
+		panic("invalid queue ratios")
+	}
+	return &TwoQ{
+		capacity: n,
+		kIn:      maxInt(1, int(float64(n)*inRatio)),
+		kOut:     maxInt(1, int(float64(n)*outRatio)),
+		a1in:     list.New(),
+		a1out:    list.New(),
+		am:       list.New(),
+		items:    make(map[interface{}]*list.Element),
+	}
+}
+
+// Add добавляет значение. Существующий резидентный ключ обновляется и возвращается false;
+// ключ, найденный в A1out, переходит в Am
+func (q *TwoQ) Add(key, value interface{}) bool {
+	if element, ok := q.items[key]; ok {
+		e := element.Value.(*entry)
+		switch e.queue {
+		case q.am:
+			e.value = value
+			q.am.MoveToFront(element)
+			return false
+		case q.a1in:
+			e.value = value
+			return false
+		case q.a1out:
+			q.a1out.Remove(element)
+			delete(q.items, key)
+			q.reclaim()
+			q.push(q.am, key, value)
+			return true
+		}
+	}
+
+	q.reclaim()
+	q.push(q.a1in, key, value)
+	return true
+}
+
+// Get возвращает резидентное значение. Попадание в Am повышает приоритет, A1in остаётся FIFO
+func (q *TwoQ) Get(key interface{}) (value interface{}, ok bool) {
+	element, exists := q.items[key]
+	if !exists {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	switch e.queue {
+	case q.am:
+		q.am.MoveToFront(element)
+	case q.a1out:
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Remove удаляет резидентный элемент; призрачная запись ключа тоже забывается
+func (q *TwoQ) Remove(key interface{}) (ok bool) {
+	element, exists := q.items[key]
+	if !exists {
+		return false
+	}
+	e := element.Value.(*entry)
+	e.queue.Remove(element)
+	delete(q.items, key)
+	return e.queue != q.a1out
+}
+
+// Len возвращает количество резидентных элементов
+func (q *TwoQ) Len() int {
+	return q.a1in.Len() + q.am.Len()
+}
+
+// Clear очищает все очереди, включая призрачную A1out
+func (q *TwoQ) Clear() {
+	q.a1in.Init()
+	q.a1out.Init()
+	q.am.Init()
+	q.items = make(map[interface{}]*list.Element)
+}
+
+// reclaim освобождает место под новый элемент, если кэш заполнен
+func (q *TwoQ) reclaim() {
+	if q.Len() < q.capacity {
+		return
+	}
+
+	if q.a1in.Len() > q.kIn || q.am.Len() == 0 {
+		// Старейший элемент A1in уходит в A1out
+		element := q.a1in.Back()
+		e := q.a1in.Remove(element).(*entry)
+		e.value = nil
+		e.queue = q.a1out
+		q.items[e.key] = q.a1out.PushFront(e)
+		if q.a1out.Len() > q.kOut {
+			ghost := q.a1out.Remove(q.a1out.Back()).(*entry)
+			delete(q.items, ghost.key)
+		}
+		return
+	}
+
+	e := q.am.Remove(q.am.Back()).(*entry)
+	delete(q.items, e.key)
+}
+
+// push добавляет резидентный элемент в начало очереди
+func (q *TwoQ) push(queue *list.List, key, value interface{}) {
+	q.items[key] = queue.PushFront(&entry{key: key, value: value, queue: queue})
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
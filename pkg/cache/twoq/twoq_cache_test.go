@@ -0,0 +1,72 @@
+package twoq
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTwoQ_Basic проверяет базовые операции интерфейса
+func TestTwoQ_Basic(t *testing.T) {
+	q := NewTwoQCache(4).(*TwoQ)
+
+	assert.True(t, q.Add("a", 1))
+	assert.False(t, q.Add("a", 10))
+
+	val, ok := q.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = q.Get("missing")
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	assert.True(t, q.Remove("a"))
+	assert.False(t, q.Remove("a"))
+	assert.Equal(t, 0, q.Len())
+}
+
+// TestTwoQ_SecondAccessPromotesToAm проверяет переход ключа из A1out в Am
+func TestTwoQ_SecondAccessPromotesToAm(t *testing.T) {
+	q := NewTwoQCacheWithRatios(4, 0.25, 0.5).(*TwoQ)
+	q.Add("hot", 0)
+	for i := 0; i < 4; i++ {
+		q.Add(fmt.Sprintf("cold%d", i), i)
+	}
+
+	_, ok := q.Get("hot")
+	assert.False(t, ok, "hot should be pushed out of A1in")
+	assert.Equal(t, q.a1out, q.items["hot"].Value.(*entry).queue, "hot should be remembered in A1out")
+
+	q.Add("hot", 0) // второе обращение
+	assert.Equal(t, q.am, q.items["hot"].Value.(*entry).queue, "hot should move to Am")
+}
+
+// TestTwoQ_AmSurvivesScan проверяет, что ключ из Am переживает поток одноразовых ключей
+func TestTwoQ_AmSurvivesScan(t *testing.T) {
+	q := NewTwoQCache(4).(*TwoQ)
+	q.Add("hot", 0)
+	q.Add("once", 0)
+	for i := 0; i < 4; i++ {
+		q.Add(fmt.Sprintf("warm%d", i), i)
+	}
+	q.Add("hot", 0) // hot переходит в Am
+
+	for i := 0; i < 20; i++ {
+		q.Add(fmt.Sprintf("scan%d", i), i)
+		assert.LessOrEqual(t, q.Len(), 4, "Resident size should not exceed capacity")
+	}
+
+	_, ok := q.Get("hot")
+	assert.True(t, ok, "Key from Am should survive the scan")
+	_, ok = q.Get("once")
+	assert.False(t, ok, "One-shot key should be evicted")
+	assert.LessOrEqual(t, q.a1out.Len(), q.kOut, "A1out should stay bounded")
+}
+
+// TestTwoQ_InvalidArguments проверяет панику на некорректных параметрах
+func TestTwoQ_InvalidArguments(t *testing.T) {
+	assert.Panics(t, func() { NewTwoQCache(0) })
+	assert.Panics(t, func() { NewTwoQCacheWithRatios(4, 1.5, 0.5) })
+}